@@ -1,8 +1,8 @@
 package influxdb
 
 import (
-	"bufio"
 	"io"
+	"sync"
 )
 
 const (
@@ -16,9 +16,18 @@ var _ Writer = &BufferedWriter{}
 // buffer to be exceeded, it will first flush the contents and then initiate
 // the write. This is to avoid splitting line protocol between two separate
 // writes.
+//
+// Unlike bufio.Writer, an error from the underlying Writer is not permanent.
+// Any data that could not be written remains in the buffer and will be
+// retried by the next Flush.
+//
+// A BufferedWriter is safe for concurrent use. Calls to Write and Flush are
+// serialized so the contents of concurrent writes are never interleaved.
 type BufferedWriter struct {
-	bw *bufio.Writer
-	p  Protocol
+	mu  sync.Mutex
+	w   io.Writer
+	buf []byte
+	p   Protocol
 }
 
 // NewBufferedWriter creates a new BufferedWriter. If the io.Writer passed in
@@ -33,10 +42,14 @@ func NewBufferedWriterSize(w io.Writer, size int) *BufferedWriter {
 	if w, ok := w.(Writer); ok {
 		protocol = w.Protocol()
 	}
+	if size <= 0 {
+		size = defaultBufSize
+	}
 
 	return &BufferedWriter{
-		bw: bufio.NewWriterSize(w, size),
-		p:  protocol,
+		w:   w,
+		buf: make([]byte, 0, size),
+		p:   protocol,
 	}
 }
 
@@ -46,18 +59,71 @@ func (w *BufferedWriter) Protocol() Protocol {
 }
 
 func (w *BufferedWriter) Write(p []byte) (n int, err error) {
-	if len(p) > w.bw.Available() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(p) > cap(w.buf)-len(w.buf) {
 		// Flush the data in the buffer before writing.
-		if w.bw.Buffered() != 0 {
-			if err = w.bw.Flush(); err != nil {
+		if len(w.buf) != 0 {
+			if err = w.flush(); err != nil {
 				return n, err
 			}
 		}
+
+		// The data is larger than the entire buffer so write it directly.
+		if len(p) > cap(w.buf) {
+			return w.w.Write(p)
+		}
 	}
-	return w.bw.Write(p)
+	w.buf = append(w.buf, p...)
+	return len(p), nil
 }
 
 // Flush causes any bytes buffered to be written to the underlying Writer.
 func (w *BufferedWriter) Flush() error {
-	return w.bw.Flush()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flush()
+}
+
+// Buffered returns the number of bytes that have been written into the
+// buffer and not yet flushed.
+func (w *BufferedWriter) Buffered() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.buf)
+}
+
+// flush writes the buffer to the underlying Writer. Any data that was not
+// written is kept at the start of the buffer. The lock must be held.
+func (w *BufferedWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+
+	n, err := w.w.Write(w.buf)
+	if n < len(w.buf) && err == nil {
+		err = io.ErrShortWrite
+	}
+	if n > 0 {
+		w.buf = w.buf[:copy(w.buf, w.buf[n:])]
+	}
+	return err
+}
+
+// flushOrDiscard flushes the buffer. If the flush fails and discard returns
+// true for the error, the data remaining in the buffer is discarded and its
+// length is returned.
+func (w *BufferedWriter) flushOrDiscard(discard func(err error) bool) (discarded int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.flush(); err != nil {
+		if discard(err) {
+			discarded = len(w.buf)
+			w.buf = w.buf[:0]
+		}
+		return discarded, err
+	}
+	return 0, nil
 }
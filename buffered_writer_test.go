@@ -90,3 +90,27 @@ func TestWritePoints_BufferedWriter(t *testing.T) {
 		t.Fatalf("unexpected buffer length: have=%#v want=%#v", have, want)
 	}
 }
+
+func TestBufferedWriter_RetryAfterError(t *testing.T) {
+	fw := &failingWriter{fail: true}
+	w := influxdb.NewBufferedWriterSize(fw, 32)
+
+	if _, err := w.Write([]byte("cpu value=5\n")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := w.Flush(); err == nil {
+		t.Fatal("expected error")
+	} else if have, want := w.Buffered(), 12; have != want {
+		t.Fatalf("unexpected buffered length: have=%#v want=%#v", have, want)
+	}
+
+	// The data should be retained and written once the writer recovers.
+	fw.setFail(false)
+	if err := w.Flush(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if have, want := fw.buf.String(), "cpu value=5\n"; have != want {
+		t.Fatalf("unexpected output: have=%#v want=%#v", have, want)
+	} else if have, want := w.Buffered(), 0; have != want {
+		t.Fatalf("unexpected buffered length: have=%#v want=%#v", have, want)
+	}
+}
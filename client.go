@@ -14,6 +14,8 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
+	"unsafe"
 )
 
 const (
@@ -50,6 +52,23 @@ type Client struct {
 
 	// Auth holds the authentication credentials.
 	Auth *Auth
+
+	// Logger receives debug and warning events for requests made by this
+	// client and the writers created from it. If this is left nil, nothing
+	// is logged.
+	Logger Logger
+
+	// Interceptors wrap every query, write and ping request sent by this
+	// client. The first Interceptor is the outermost one and sees the
+	// request first and the response last.
+	Interceptors []Interceptor
+
+	// SlowQueryThreshold causes a warning to be logged for any query whose
+	// response takes longer than the threshold. If this is zero, slow queries
+	// are not reported.
+	SlowQueryThreshold time.Duration
+
+	stats unsafe.Pointer // *clientStats
 }
 
 // NewClient creates a new client pointed to the parsed hostname.
@@ -84,10 +103,12 @@ type ServerInfo struct {
 func (c *Client) Ping() (ServerInfo, error) {
 	u := c.url("/ping")
 	req := newRequest("GET", u.String(), nil)
-	resp, err := c.Client.Do(req)
+	resp, err := c.do(OperationPing, req)
 	if err != nil {
 		return ServerInfo{}, ErrPing{Cause: err}
-	} else if resp.StatusCode/100 != 2 {
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
 		return ServerInfo{}, ErrPing{Cause: errors.New("incorrect status code")}
 	}
 	return ServerInfo{
@@ -202,7 +223,13 @@ func (c *Client) newQueryRequest(q interface{}, readonly bool, opt QueryOptions)
 		}
 		values.Set("params", string(pout))
 	}
-	values.Set("epoch", PrecisionNanosecond.String())
+	if !opt.RFC3339 {
+		epoch := opt.Epoch
+		if epoch == "" {
+			epoch = PrecisionNanosecond
+		}
+		values.Set("epoch", epoch.String())
+	}
 
 	u := c.url("/query")
 	u.RawQuery = values.Encode()
@@ -274,6 +301,19 @@ func (c *Client) url(path string) url.URL {
 	return u
 }
 
+// logger returns the Logger for this client.
+func (c *Client) logger() Logger {
+	return loggerOrNop(c.Logger)
+}
+
+// requestID returns the request id the server assigned to the response.
+func requestID(resp *http.Response) string {
+	if id := resp.Header.Get("X-Request-Id"); id != "" {
+		return id
+	}
+	return resp.Header.Get("Request-Id")
+}
+
 // newRequest constructs a new request and sets the default headers.
 func newRequest(method, url string, body io.Reader) *http.Request {
 	req, _ := http.NewRequest(method, url, body)
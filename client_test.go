@@ -61,7 +61,7 @@ func TestClient_Do(t *testing.T) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		t.Errorf("StatusCode = %q; want %q", resp.StatusCode, http.StatusNoContent)
+		t.Errorf("StatusCode = %d; want %d", resp.StatusCode, http.StatusNoContent)
 	}
 
 	select {
@@ -377,7 +377,7 @@ func TestClient_NewReadonlyQueryRequest_ioReader(t *testing.T) {
 
 func TestClient_Select(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if got, want := r.Method, "POST"; got != want {
+		if got, want := r.Method, "GET"; got != want {
 			t.Errorf("Method = %q; want %q", got, want)
 		}
 
@@ -437,7 +437,7 @@ func TestClient_Select(t *testing.T) {
 	})
 
 	exp := [][]interface{}{
-		[]interface{}{"1970-01-01T00:00:00Z", float64(5)},
+		[]interface{}{"1970-01-01T00:00:00Z", int64(5)},
 	}
 	if !reflect.DeepEqual(got, exp) {
 		t.Fatalf("Values = %q; want %q", got, exp)
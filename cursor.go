@@ -2,12 +2,20 @@ package influxdb
 
 import (
 	"io"
+	"iter"
 	"time"
 )
 
 // Cursor is a cursor that reads and decodes a ResultSet.
+//
+// A Cursor must be closed when it is no longer needed. Results are decoded
+// by a goroutine that reads the response as rows are requested, and that
+// goroutine and the underlying connection are only released by Close or by
+// reading every result. Iterating with Results or Rows closes the Cursor
+// when the loop ends.
 type Cursor struct {
-	cur cursor
+	cur             cursor
+	continueOnError bool
 }
 
 // NextSet will return the next ResultSet. This invalidates the previous
@@ -20,12 +28,21 @@ func (c *Cursor) NextSet() (*ResultSet, error) { return c.cur.NextSet() }
 // Close closes the cursor so the underlying stream will be closed if one exists.
 func (c *Cursor) Close() error { return c.cur.Close() }
 
-// Each iterates over every ResultSet in the Cursor.
+// Each iterates over every ResultSet in the Cursor. If the Cursor continues
+// past failed statements, every ErrResult is collected and returned as an
+// ErrResults after the remaining statements have been read.
 func (c *Cursor) Each(fn func(*ResultSet) error) error {
+	var errs ErrResults
 	for {
 		result, err := c.NextSet()
 		if err != nil {
-			if err == io.EOF {
+			if e, ok := c.skippable(err); ok {
+				errs = append(errs, e)
+				continue
+			} else if err == io.EOF {
+				if len(errs) > 0 {
+					return errs
+				}
 				return nil
 			}
 			return err
@@ -40,6 +57,92 @@ func (c *Cursor) Each(fn func(*ResultSet) error) error {
 	}
 }
 
+// Results returns an iterator over every ResultSet in the Cursor. If reading
+// a ResultSet fails, the error is yielded with a nil ResultSet and the
+// iteration ends unless the Cursor continues past failed statements. The
+// Cursor is closed when the iteration ends, including when the loop is
+// exited early.
+func (c *Cursor) Results() iter.Seq2[*ResultSet, error] {
+	return func(yield func(*ResultSet, error) bool) {
+		defer c.Close()
+		for {
+			result, err := c.NextSet()
+			if err != nil {
+				if _, ok := c.skippable(err); ok {
+					if !yield(nil, err) {
+						return
+					}
+					continue
+				} else if err != io.EOF {
+					yield(nil, err)
+				}
+				return
+			}
+
+			if !yield(result, nil) {
+				return
+			}
+		}
+	}
+}
+
+// SeriesRow is a Row along with the ResultSet and Series that contain it.
+type SeriesRow struct {
+	Result *ResultSet
+	Series *Series
+	Row    Row
+}
+
+// Rows returns an iterator over every Row in every Series of every ResultSet
+// in the Cursor. If reading fails, the error is yielded with an empty
+// SeriesRow and the iteration ends. The Cursor is closed when the iteration
+// ends, including when the loop is exited early.
+func (c *Cursor) Rows() iter.Seq2[SeriesRow, error] {
+	return func(yield func(SeriesRow, error) bool) {
+	results:
+		for result, err := range c.Results() {
+			if err != nil {
+				if _, ok := c.skippable(err); !yield(SeriesRow{}, err) || !ok {
+					return
+				}
+				continue
+			}
+
+			for series, err := range result.AllSeries() {
+				if err != nil {
+					if _, ok := c.skippable(err); !yield(SeriesRow{}, err) || !ok {
+						return
+					}
+					continue results
+				}
+
+				for row, err := range series.Rows() {
+					if err != nil {
+						if _, ok := c.skippable(err); !yield(SeriesRow{}, err) || !ok {
+							return
+						}
+						continue results
+					}
+
+					if !yield(SeriesRow{Result: result, Series: series, Row: row}, nil) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// skippable returns the ErrResult if the error is for a single statement and
+// the Cursor continues past failed statements.
+func (c *Cursor) skippable(err error) (ErrResult, bool) {
+	e, ok := err.(ErrResult)
+	if !ok || !c.continueOnError || e.StatementID < 0 {
+		return ErrResult{}, false
+	}
+	return e, true
+}
+
 // cursor is a cursor that reads and decodes a ResultSet.
 type cursor interface {
 	NextSet() (*ResultSet, error)
@@ -51,6 +154,9 @@ type ResultSet struct {
 	result resultSet
 }
 
+func (r *ResultSet) Columns() []string            { return r.result.Columns() }
+func (r *ResultSet) StatementID() int             { return r.result.StatementID() }
+func (r *ResultSet) Statement() string            { return r.result.Statement() }
 func (r *ResultSet) Messages() []*Message         { return r.result.Messages() }
 func (r *ResultSet) NextSeries() (*Series, error) { return r.result.NextSeries() }
 
@@ -74,8 +180,41 @@ func (r *ResultSet) Each(fn func(*Series) error) error {
 	}
 }
 
+// AllSeries returns an iterator over every Series in the ResultSet. If
+// reading a Series fails, the error is yielded with a nil Series and the
+// iteration ends. Any Series not read when the loop exits early are
+// discarded by the next call to NextSet on the Cursor.
+func (r *ResultSet) AllSeries() iter.Seq2[*Series, error] {
+	return func(yield func(*Series, error) bool) {
+		for {
+			series, err := r.NextSeries()
+			if err != nil {
+				if err != io.EOF {
+					yield(nil, err)
+				}
+				return
+			}
+
+			if !yield(series, nil) {
+				return
+			}
+		}
+	}
+}
+
 // resultSet encapsulates a result from a single command.
 type resultSet interface {
+	// Columns returns the column names associated with this ResultSet.
+	Columns() []string
+
+	// StatementID returns the id of the statement that produced this
+	// ResultSet.
+	StatementID() int
+
+	// Statement returns the text of the statement that produced this
+	// ResultSet. It is empty if the statements of the query are not known.
+	Statement() string
+
 	// Messages returns the informational messages sent by the server for this ResultSet.
 	Messages() []*Message
 
@@ -114,6 +253,26 @@ func (s *Series) Each(fn func(Row) error) error {
 	}
 }
 
+// Rows returns an iterator over every Row in the Series. If reading a Row
+// fails, the error is yielded with a nil Row and the iteration ends.
+func (s *Series) Rows() iter.Seq2[Row, error] {
+	return func(yield func(Row, error) bool) {
+		for {
+			row, err := s.s.NextRow()
+			if err != nil {
+				if err != io.EOF {
+					yield(nil, err)
+				}
+				return
+			}
+
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
 // series encapsulates a series within a ResultSet.
 type series interface {
 	// Name returns the measurement name associated with this series.
@@ -138,6 +297,9 @@ type series interface {
 // Row is a row of values in the ResultSet.
 type Row interface {
 	// Time returns the time column as a time.Time if it exists in the Row.
+	// Times sent as an epoch are returned in UTC. Times sent as an RFC3339
+	// string keep the offset they were sent with, such as the offset of a
+	// tz() clause.
 	Time() time.Time
 
 	// Value returns value at index. If an invalid index is given, this will panic.
@@ -149,15 +311,111 @@ type Row interface {
 	// ValueByName returns the value by a named column. If the column does not
 	// exist, this will return nil.
 	ValueByName(column string) interface{}
+
+	// The typed accessors below read a column by index or by name and
+	// convert it to the type. They return ErrNoColumn if the column does not
+	// exist, ErrNullValue if the value is null and ErrColumnType if the value
+	// cannot be converted to the type. Use Nullable to treat a null value as
+	// a missing value instead of an error.
+
+	// Float returns the value at index as a float64. Integers are converted.
+	Float(index int) (float64, error)
+
+	// FloatByName returns the value of the named column as a float64.
+	FloatByName(column string) (float64, error)
+
+	// Int returns the value at index as an int64. Unsigned integers and
+	// integral floats are converted if they fit.
+	Int(index int) (int64, error)
+
+	// IntByName returns the value of the named column as an int64.
+	IntByName(column string) (int64, error)
+
+	// Uint returns the value at index as a uint64. Non-negative integers and
+	// integral floats are converted if they fit.
+	Uint(index int) (uint64, error)
+
+	// UintByName returns the value of the named column as a uint64.
+	UintByName(column string) (uint64, error)
+
+	// String returns the value at index as a string.
+	String(index int) (string, error)
+
+	// StringByName returns the value of the named column as a string.
+	StringByName(column string) (string, error)
+
+	// Bool returns the value at index as a bool.
+	Bool(index int) (bool, error)
+
+	// BoolByName returns the value of the named column as a bool.
+	BoolByName(column string) (bool, error)
+
+	// TimeAt returns the value at index as a time.Time. The value may be an
+	// RFC3339 string or a time since the epoch in the precision of the
+	// cursor.
+	TimeAt(index int) (time.Time, error)
+
+	// TimeByName returns the value of the named column as a time.Time.
+	TimeByName(column string) (time.Time, error)
+
+	// Duration returns the value at index as a time.Duration. The value may
+	// be a duration string, such as 1h0m0s, or a number of nanoseconds.
+	Duration(index int) (time.Duration, error)
+
+	// DurationByName returns the value of the named column as a time.Duration.
+	DurationByName(column string) (time.Duration, error)
+}
+
+// CursorOptions is a set of configuration options for decoding a Cursor.
+type CursorOptions struct {
+	// FloatNumbers decodes every number as a float64 the same way earlier
+	// versions of this client did. By default, integral numbers are decoded
+	// as an int64 so large integers keep their precision. Integral numbers
+	// too large for an int64 are decoded as a uint64 and any other number is
+	// decoded as a float64.
+	FloatNumbers bool
+
+	// Epoch is the precision of the times in the results when they are sent
+	// as a number. This must match the epoch the query was made with. If
+	// this is empty, nanosecond precision is assumed.
+	Epoch Precision
+
+	// MaxBufferedRows is the maximum number of rows read from the response
+	// and held in memory before they are returned. Results with more rows
+	// are returned in pieces the same as a chunked response, so only the
+	// rows that have been read are counted by Series.Len and the messages
+	// of the result are only available after all of its series have been
+	// read. If this is zero, DefaultMaxBufferedRows is used.
+	MaxBufferedRows int
+
+	// Statements is the text of each statement in the query. It is used to
+	// report the statement that produced each ResultSet and ErrResult. See
+	// SplitStatements.
+	Statements []string
+
+	// ContinueOnError causes Cursor.Each, Cursor.Results and Cursor.Rows to
+	// continue with the next statement when a statement fails instead of
+	// stopping. NextSet always continues with the next statement when it is
+	// called after returning an ErrResult.
+	ContinueOnError bool
 }
 
 // NewCursor constructs a new cursor from the io.ReadCloser and parses it with
 // the appropriate decoder for the format. The following formatters are supported:
 // json (application/json)
 func NewCursor(r io.ReadCloser, format string) (*Cursor, error) {
+	return NewCursorOptions(r, format, CursorOptions{})
+}
+
+// NewCursorOptions constructs a new cursor the same as NewCursor, but
+// decodes the results using the options.
+func NewCursorOptions(r io.ReadCloser, format string, opt CursorOptions) (*Cursor, error) {
 	switch format {
 	case "json", "application/json":
-		return &Cursor{cur: newJSONCursor(r)}, nil
+		return &Cursor{
+			cur:             newJSONCursor(r, opt),
+			continueOnError: opt.ContinueOnError,
+		}, nil
 	default:
 		return nil, ErrUnknownFormat{Format: format}
 	}
@@ -2,8 +2,10 @@ package influxdb_test
 
 import (
 	"bytes"
+	"io"
 	"io/ioutil"
 	"reflect"
+	"strings"
 	"testing"
 
 	influxdb "github.com/influxdata/influxdb-client"
@@ -16,3 +18,201 @@ func TestNewCursor_UnknownFormat(t *testing.T) {
 		t.Fatalf("got %#v; want %#v", got, want)
 	}
 }
+
+// closeRecorder records whether the reader has been closed.
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+const iterResults = `{"results":[` +
+	`{"series":[{"name":"cpu","tags":{"host":"server01"},"columns":["time","value"],"values":[[0,1],[10,2]]},{"name":"cpu","tags":{"host":"server02"},"columns":["time","value"],"values":[[0,3]]}]},` +
+	`{"series":[{"name":"mem","columns":["time","value"],"values":[[0,4]]}]}` +
+	`]}`
+
+func TestCursor_Rows(t *testing.T) {
+	r := &closeRecorder{Reader: strings.NewReader(iterResults)}
+	cur, err := influxdb.NewCursor(r, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type row struct {
+		Name  string
+		Host  string
+		Value interface{}
+	}
+	var got []row
+	for sr, err := range cur.Rows() {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		var host string
+		for _, tag := range sr.Series.Tags() {
+			if tag.Key == "host" {
+				host = tag.Value
+			}
+		}
+		got = append(got, row{Name: sr.Series.Name(), Host: host, Value: sr.Row.Value(1)})
+	}
+
+	want := []row{
+		{Name: "cpu", Host: "server01", Value: int64(1)},
+		{Name: "cpu", Host: "server01", Value: int64(2)},
+		{Name: "cpu", Host: "server02", Value: int64(3)},
+		{Name: "mem", Value: int64(4)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v; want %#v", got, want)
+	}
+	if !r.closed {
+		t.Fatal("expected the cursor to be closed")
+	}
+}
+
+func TestCursor_Results_EarlyExit(t *testing.T) {
+	r := &closeRecorder{Reader: strings.NewReader(iterResults)}
+	cur, err := influxdb.NewCursor(r, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+	for result, err := range cur.Results() {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		for series, err := range result.AllSeries() {
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			for range series.Rows() {
+				n++
+				break
+			}
+		}
+		break
+	}
+
+	if got, want := n, 2; got != want {
+		t.Fatalf("got %d rows; want %d", got, want)
+	}
+	if !r.closed {
+		t.Fatal("expected the cursor to be closed")
+	}
+}
+
+func TestCursor_Rows_Error(t *testing.T) {
+	r := &closeRecorder{Reader: strings.NewReader(`{"results":[{"series":[{"name":"cpu","columns":["time","value"],"values":[[0,1]]}]},{"error":"expected err"}]}`)}
+	cur, err := influxdb.NewCursor(r, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		n    int
+		errs []error
+	)
+	for _, err := range cur.Rows() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		n++
+	}
+
+	if got, want := n, 1; got != want {
+		t.Fatalf("got %d rows; want %d", got, want)
+	}
+	if want := []error{influxdb.ErrResult{Err: "expected err", StatementID: 1}}; !reflect.DeepEqual(errs, want) {
+		t.Fatalf("got errors %#v; want %#v", errs, want)
+	}
+	if !r.closed {
+		t.Fatal("expected the cursor to be closed")
+	}
+}
+
+const failedResults = `{"results":[` +
+	`{"statement_id":0,"series":[{"name":"cpu","columns":["time","value"],"values":[[0,1]]}]},` +
+	`{"statement_id":1,"error":"measurement not found"},` +
+	`{"statement_id":2,"series":[{"name":"mem","columns":["time","value"],"values":[[0,2]]}]},` +
+	`{"statement_id":3,"error":"not executed"}` +
+	`]}`
+
+func TestCursor_NextSet_AfterError(t *testing.T) {
+	cur, err := influxdb.NewCursor(ioutil.NopCloser(strings.NewReader(failedResults)), "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result, err := cur.NextSet(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if got, want := result.StatementID(), 0; got != want {
+		t.Fatalf("StatementID = %d; want %d", got, want)
+	}
+	if _, err := cur.NextSet(); err != (influxdb.ErrResult{Err: "measurement not found", StatementID: 1}) {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+	if result, err := cur.NextSet(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if got, want := result.StatementID(), 2; got != want {
+		t.Fatalf("StatementID = %d; want %d", got, want)
+	}
+}
+
+func TestCursor_ContinueOnError(t *testing.T) {
+	open := func(continueOnError bool) *influxdb.Cursor {
+		cur, err := influxdb.NewCursorOptions(ioutil.NopCloser(strings.NewReader(failedResults)), "json", influxdb.CursorOptions{
+			Statements:      []string{"SELECT value FROM cpu", "SELECT value FROM foo", "SELECT value FROM mem", "SELECT value FROM disk"},
+			ContinueOnError: continueOnError,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return cur
+	}
+
+	want := influxdb.ErrResults{
+		{Err: "measurement not found", StatementID: 1, Statement: "SELECT value FROM foo"},
+		{Err: "not executed", StatementID: 3, Statement: "SELECT value FROM disk"},
+	}
+
+	// Each stops at the first failure unless continuing.
+	n := 0
+	err := open(false).Each(func(*influxdb.ResultSet) error { n++; return nil })
+	if err != want[0] {
+		t.Fatalf("got error %#v; want %#v", err, want[0])
+	} else if n != 1 {
+		t.Fatalf("got %d results; want %d", n, 1)
+	}
+
+	n = 0
+	err = open(true).Each(func(*influxdb.ResultSet) error { n++; return nil })
+	if !reflect.DeepEqual(err, want) {
+		t.Fatalf("got error %#v; want %#v", err, want)
+	} else if n != 2 {
+		t.Fatalf("got %d results; want %d", n, 2)
+	}
+
+	var (
+		values []interface{}
+		errs   influxdb.ErrResults
+	)
+	for sr, err := range open(true).Rows() {
+		if err != nil {
+			errs = append(errs, err.(influxdb.ErrResult))
+			continue
+		}
+		values = append(values, sr.Row.Value(1))
+	}
+	if !reflect.DeepEqual(errs, want) {
+		t.Fatalf("got errors %#v; want %#v", errs, want)
+	} else if want := []interface{}{int64(1), int64(2)}; !reflect.DeepEqual(values, want) {
+		t.Fatalf("got %#v; want %#v", values, want)
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"strings"
 )
 
 var (
@@ -15,9 +17,17 @@ var (
 	// ErrNoFields is returned when attempting to write with no fields.
 	ErrNoFields = errors.New("no fields")
 
+	// ErrInvalidFloat is returned when attempting to write a NaN or infinite
+	// float value. The server is unable to store these values.
+	ErrInvalidFloat = errors.New("invalid float: NaN and Inf are not supported")
+
 	// ErrSeriesTruncated is returned when a series has been truncated and can
 	// no longer return more values.
 	ErrSeriesTruncated = errors.New("truncated output")
+
+	// ErrWriterClosed is returned when attempting to write to a writer that
+	// has already been closed.
+	ErrWriterClosed = errors.New("writer closed")
 )
 
 // ErrPing wraps the error returned when attempting to ping the server and it fails.
@@ -29,6 +39,11 @@ func (e ErrPing) Error() string {
 	return fmt.Sprintf("ping failed: %s", e.Cause)
 }
 
+// Unwrap returns the cause of the failed ping.
+func (e ErrPing) Unwrap() error {
+	return e.Cause
+}
+
 // ErrUnknownFormat is returned whenever an unknown cursor format is used.
 type ErrUnknownFormat struct {
 	Format string
@@ -41,31 +56,276 @@ func (e ErrUnknownFormat) Error() string {
 // ErrResult wraps an error returned for a statement in a query.
 type ErrResult struct {
 	Err string
+
+	// StatementID is the id of the statement that failed. It is -1 if the
+	// error is for the entire query instead of a single statement.
+	StatementID int
+
+	// Statement is the text of the statement that failed. It is empty if
+	// the statements of the query are not known.
+	Statement string
 }
 
 func (e ErrResult) Error() string {
 	return e.Err
 }
 
-// ErrPartialWrite is returned whenever a partial write is detected.
+// ErrResults collects the errors for every statement that failed in a query.
+// It is returned when a Cursor continues past failed statements.
+type ErrResults []ErrResult
+
+func (e ErrResults) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "%d statements failed", len(e))
+	for i, err := range e {
+		if i == 0 {
+			buf.WriteString(": ")
+		} else {
+			buf.WriteString("; ")
+		}
+		fmt.Fprintf(&buf, "statement %d: %s", err.StatementID, err.Err)
+	}
+	return buf.String()
+}
+
+// Unwrap returns each ErrResult so they can be inspected with errors.As.
+func (e ErrResults) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// ErrNoColumn is returned when reading a column that does not exist in a Row.
+type ErrNoColumn struct {
+	// Column is the name of the column that was requested. It is empty if
+	// the column was requested by index.
+	Column string
+
+	// Index is the index of the column that was requested. It is -1 if the
+	// column was requested by name.
+	Index int
+}
+
+func (e ErrNoColumn) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("column %q does not exist", e.Column)
+	}
+	return fmt.Sprintf("column index %d out of range", e.Index)
+}
+
+// ErrNullValue is returned when reading a typed value from a column that is
+// null in a Row.
+type ErrNullValue struct {
+	Column string
+}
+
+func (e ErrNullValue) Error() string {
+	return fmt.Sprintf("column %q is null", e.Column)
+}
+
+// ErrColumnType is returned when reading a typed value from a column that
+// holds a value that cannot be converted to that type.
+type ErrColumnType struct {
+	// Column is the name of the column.
+	Column string
+
+	// Type is the name of the type that was requested.
+	Type string
+
+	// Value is the value held by the column.
+	Value interface{}
+}
+
+func (e ErrColumnType) Error() string {
+	return fmt.Sprintf("column %q: cannot convert %T to %s", e.Column, e.Value, e.Type)
+}
+
+// ErrInvalidPoint is returned when a Point fails validation.
+type ErrInvalidPoint struct {
+	// Name is the measurement name of the invalid Point.
+	Name string
+
+	// Key is the tag or field key that caused the Point to be invalid. It is
+	// empty if the problem is not associated with a specific key.
+	Key string
+
+	// Reason describes why the Point is invalid.
+	Reason string
+}
+
+func (e ErrInvalidPoint) Error() string {
+	if e.Key != "" {
+		return fmt.Sprintf("invalid point %q: %s: %s", e.Name, e.Key, e.Reason)
+	}
+	return fmt.Sprintf("invalid point %q: %s", e.Name, e.Reason)
+}
+
+// ErrPartialWrite is returned whenever a partial write is detected. The
+// points that were not dropped were written.
 type ErrPartialWrite struct {
+	// Err is the error message sent by the server.
 	Err string
+
+	// Dropped is the number of points the server reports it dropped.
+	Dropped int
+
+	// Reasons is the number of points dropped for each reason, such as
+	// "field type conflict", "points beyond retention policy" or
+	// "unable to parse".
+	Reasons map[string]int
+
+	// Lines are the line numbers, starting from 1, of the lines in the
+	// request that the server identified as dropped. The server does not
+	// identify every dropped line, such as the points beyond the retention
+	// policy, so this may have fewer entries than Dropped.
+	//
+	// A field type conflict only names the measurement, field and type, so
+	// every line that writes the field with that type is included. This is
+	// a best-effort superset: when the lines are written to more than one
+	// shard, some of them may have been accepted by a shard without the
+	// conflict, and this may have more entries than Dropped.
+	Lines []int
+
+	// Points are the indexes of the Points passed to WritePoints that were
+	// identified as dropped. It is only set when WritePoints writes directly
+	// to an HTTPWriter. It is derived from Lines and has the same limits.
+	Points []int
 }
 
 func (e ErrPartialWrite) Error() string {
 	return e.Err
 }
 
-// ReadError reads the HTTP response for an error and returns it.
-// It currently only supports errors sent back as JSON.
+// ErrHTTP is returned when the server responds with an unsuccessful status
+// code.
+type ErrHTTP struct {
+	// StatusCode is the status code of the response.
+	StatusCode int
+
+	// Status is the status line of the response, such as "404 Not Found".
+	Status string
+
+	// Message is the error message sent by the server. It is empty if the
+	// server did not send one.
+	Message string
+
+	// RequestID is the id the server assigned to the request. It is read
+	// from the X-Request-Id header or, for older servers, the Request-Id
+	// header.
+	RequestID string
+}
+
+func (e ErrHTTP) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("unknown http error: %s", e.Status)
+	}
+	return e.Message
+}
+
+// Is reports whether the target is an ErrHTTP with the same status code.
+// If the target has a message, the messages must also be the same. This
+// allows matching an error with errors.Is:
+//
+//	errors.Is(err, influxdb.ErrHTTP{StatusCode: http.StatusNotFound})
+func (e ErrHTTP) Is(target error) bool {
+	t, ok := target.(ErrHTTP)
+	if !ok {
+		return false
+	}
+	return t.StatusCode == e.StatusCode && (t.Message == "" || t.Message == e.Message)
+}
+
+// IsAuth reports whether the error is because the request was not
+// authenticated or the user does not have permission to perform it.
+func IsAuth(err error) bool {
+	var e ErrHTTP
+	if errors.As(err, &e) {
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+// IsNotFound reports whether the error is because the database, retention
+// policy or measurement does not exist.
+func IsNotFound(err error) bool {
+	var e ErrHTTP
+	if errors.As(err, &e) {
+		return e.StatusCode == http.StatusNotFound || strings.Contains(e.Message, "not found")
+	}
+	var r ErrResult
+	if errors.As(err, &r) {
+		return strings.Contains(r.Err, "not found")
+	}
+	return false
+}
+
+// IsFieldTypeConflict reports whether the error is because a field was
+// written with a different type than the type already stored for it.
+func IsFieldTypeConflict(err error) bool {
+	var e ErrHTTP
+	if errors.As(err, &e) {
+		return strings.Contains(e.Message, "field type conflict")
+	}
+	var pw ErrPartialWrite
+	if errors.As(err, &pw) {
+		return strings.Contains(pw.Err, "field type conflict")
+	}
+	return false
+}
+
+// IsRetryable reports whether the request may succeed if it is sent again.
+// This is true for timeouts, rate limiting and server errors that are
+// likely to be temporary.
+func IsRetryable(err error) bool {
+	var e ErrHTTP
+	if errors.As(err, &e) {
+		switch e.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests,
+			http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return strings.Contains(e.Message, "timeout")
+	}
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// isPermanent reports whether the server rejected the data itself and would
+// reject it again if it was sent unchanged. This is true for a bad request
+// and for partial writes. Other client errors, such as missing permissions
+// or a database that does not exist yet, may be resolved later.
+func isPermanent(err error) bool {
+	var pw ErrPartialWrite
+	if errors.As(err, &pw) {
+		return true
+	}
+	var e ErrHTTP
+	return errors.As(err, &e) && e.StatusCode == http.StatusBadRequest
+}
+
+// ReadError reads the HTTP response for an error and returns it as an
+// ErrHTTP. It currently only supports messages sent back as JSON.
 func ReadError(resp *http.Response) error {
+	e := ErrHTTP{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RequestID:  requestID(resp),
+	}
+
 	out, err := ioutil.ReadAll(resp.Body)
 	resp.Body.Close()
 	if err != nil || len(out) == 0 {
-		return fmt.Errorf("unknown http error: %s", resp.Status)
+		return e
 	}
 
-	msg := string(out)
+	e.Message = string(out)
 	switch resp.Header.Get("Content-Type") {
 	case "application/json":
 		var jsonErr struct {
@@ -74,8 +334,8 @@ func ReadError(resp *http.Response) error {
 		if err := json.Unmarshal(out, &jsonErr); err == nil {
 			// Ignore any errors from parsing the JSON from the server.
 			// The server may have just sent a bad message and we don't want to mask that.
-			msg = jsonErr.Error
+			e.Message = jsonErr.Error
 		}
 	}
-	return errors.New(msg)
+	return e
 }
@@ -1,6 +1,7 @@
 package influxdb_test
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -41,3 +42,101 @@ func TestReadError_UnknownError(t *testing.T) {
 		t.Errorf("unexpected error: have=%#v want=%#v", have, want)
 	}
 }
+
+func TestErrResults(t *testing.T) {
+	err := error(influxdb.ErrResults{
+		{Err: "database not found: db0", StatementID: 0},
+		{Err: "not executed", StatementID: 2},
+	})
+	if have, want := err.Error(), "2 statements failed: statement 0: database not found: db0; statement 2: not executed"; have != want {
+		t.Errorf("unexpected error: have=%#v want=%#v", have, want)
+	}
+
+	var e influxdb.ErrResult
+	if !errors.As(err, &e) {
+		t.Fatal("expected errors.As to find an ErrResult")
+	} else if have, want := e.StatementID, 0; have != want {
+		t.Errorf("unexpected statement id: have=%d want=%d", have, want)
+	}
+
+	err = influxdb.ErrResults{{Err: "expected err", StatementID: 1}}
+	if have, want := err.Error(), "expected err"; have != want {
+		t.Errorf("unexpected error: have=%#v want=%#v", have, want)
+	}
+}
+
+func TestReadError_ErrHTTP(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{
+			"Content-Type": []string{"application/json"},
+			"X-Request-Id": []string{"4a8c1e2f"},
+		},
+		Body:       ioutil.NopCloser(strings.NewReader(`{"error":"database not found: \"db0\""}`)),
+		Status:     "404 Not Found",
+		StatusCode: http.StatusNotFound,
+	}
+
+	err := influxdb.ReadError(resp)
+	want := influxdb.ErrHTTP{
+		StatusCode: http.StatusNotFound,
+		Status:     "404 Not Found",
+		Message:    `database not found: "db0"`,
+		RequestID:  "4a8c1e2f",
+	}
+	if err != want {
+		t.Fatalf("unexpected error: have=%#v want=%#v", err, want)
+	}
+
+	wrapped := fmt.Errorf("write: %w", err)
+	if !errors.Is(wrapped, influxdb.ErrHTTP{StatusCode: http.StatusNotFound}) {
+		t.Error("expected errors.Is to match the status code")
+	}
+	if errors.Is(wrapped, influxdb.ErrHTTP{StatusCode: http.StatusNotFound, Message: "other"}) {
+		t.Error("expected errors.Is not to match a different message")
+	}
+	var e influxdb.ErrHTTP
+	if !errors.As(wrapped, &e) || e.RequestID != "4a8c1e2f" {
+		t.Errorf("unexpected error: have=%#v", e)
+	}
+}
+
+// timeoutError is a net.Error that timed out.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestErrorPredicates(t *testing.T) {
+	for _, tt := range []struct {
+		name                string
+		err                 error
+		auth, notFound      bool
+		retryable, conflict bool
+	}{
+		{name: "unauthorized", err: influxdb.ErrHTTP{StatusCode: http.StatusUnauthorized, Message: "authorization failed"}, auth: true},
+		{name: "forbidden", err: influxdb.ErrHTTP{StatusCode: http.StatusForbidden}, auth: true},
+		{name: "database not found", err: influxdb.ErrHTTP{StatusCode: http.StatusNotFound, Message: `database not found: "db0"`}, notFound: true},
+		{name: "result not found", err: influxdb.ErrResult{Err: `retention policy not found: rp0`}, notFound: true},
+		{name: "field type conflict", err: influxdb.ErrHTTP{StatusCode: http.StatusBadRequest, Message: `field type conflict: input field "value" on measurement "cpu" is type integer, already exists as type float`}, conflict: true},
+		{name: "partial write conflict", err: influxdb.ErrPartialWrite{Err: "partial write: field type conflict: dropped=1"}, conflict: true},
+		{name: "body too large", err: influxdb.ErrHTTP{StatusCode: http.StatusRequestEntityTooLarge, Message: "request entity too large"}},
+		{name: "service unavailable", err: influxdb.ErrHTTP{StatusCode: http.StatusServiceUnavailable}, retryable: true},
+		{name: "server timeout", err: influxdb.ErrHTTP{StatusCode: http.StatusBadRequest, Message: "timeout"}, retryable: true},
+		{name: "network timeout", err: influxdb.ErrPing{Cause: timeoutError{}}, retryable: true},
+		{name: "other", err: errors.New("expected err")},
+	} {
+		if got := influxdb.IsAuth(tt.err); got != tt.auth {
+			t.Errorf("%s: IsAuth = %v; want %v", tt.name, got, tt.auth)
+		}
+		if got := influxdb.IsNotFound(tt.err); got != tt.notFound {
+			t.Errorf("%s: IsNotFound = %v; want %v", tt.name, got, tt.notFound)
+		}
+		if got := influxdb.IsRetryable(tt.err); got != tt.retryable {
+			t.Errorf("%s: IsRetryable = %v; want %v", tt.name, got, tt.retryable)
+		}
+		if got := influxdb.IsFieldTypeConflict(tt.err); got != tt.conflict {
+			t.Errorf("%s: IsFieldTypeConflict = %v; want %v", tt.name, got, tt.conflict)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package influxdb
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// StatementKind is the kind of an InfluxQL statement.
+type StatementKind int
+
+const (
+	// ReadStatement is a statement that only reads from the database, such
+	// as SELECT, SHOW and EXPLAIN.
+	ReadStatement StatementKind = iota
+
+	// WriteStatement is a statement that modifies the data in the database,
+	// such as SELECT INTO, DELETE, DROP SERIES and DROP MEASUREMENT. Any
+	// statement that is not recognized is also a WriteStatement.
+	WriteStatement
+
+	// AdminStatement is a statement that manages the server, such as
+	// creating or dropping databases, retention policies, users, continuous
+	// queries and subscriptions, granting privileges and killing queries.
+	AdminStatement
+)
+
+func (k StatementKind) String() string {
+	switch k {
+	case ReadStatement:
+		return "read"
+	case WriteStatement:
+		return "write"
+	case AdminStatement:
+		return "admin"
+	default:
+		return "unknown"
+	}
+}
+
+// QueryStatement is a single statement from a query.
+type QueryStatement struct {
+	// Text is the text of the statement without the semicolon separating it
+	// from the next statement or any surrounding whitespace and comments.
+	Text string
+
+	// Kind is the kind of the statement.
+	Kind StatementKind
+}
+
+// SplitStatements splits the query into its statements. Statements are
+// separated by semicolons that are not within a string, quoted identifier,
+// regular expression or comment. Statements that are empty or only
+// contain comments are omitted the same as the server does.
+func SplitStatements(q string) []QueryStatement {
+	var (
+		stmts []QueryStatement
+		words []string
+		start = -1
+		end   int
+	)
+	s := influxqlScanner{s: q}
+	for {
+		tok, lit, pos := s.scan()
+		switch tok {
+		case influxqlWS, influxqlComment:
+			continue
+		case influxqlSemicolon, influxqlEOF:
+			if start >= 0 {
+				stmts = append(stmts, QueryStatement{
+					Text: q[start:end],
+					Kind: classifyWords(words),
+				})
+			}
+			if tok == influxqlEOF {
+				return stmts
+			}
+			words, start = words[:0], -1
+			continue
+		case influxqlIdent:
+			words = append(words, lit)
+		}
+
+		if start < 0 {
+			start = pos
+		}
+		end = s.pos
+	}
+}
+
+// ClassifyStatement returns the kind of the first statement in the query.
+func ClassifyStatement(stmt string) StatementKind {
+	stmts := SplitStatements(stmt)
+	if len(stmts) == 0 {
+		return WriteStatement
+	}
+	return stmts[0].Kind
+}
+
+// isReadonlyQuery reports whether the query has statements and all of them
+// only read from the database.
+func isReadonlyQuery(q string) bool {
+	stmts := SplitStatements(q)
+	for _, stmt := range stmts {
+		if stmt.Kind != ReadStatement {
+			return false
+		}
+	}
+	return len(stmts) > 0
+}
+
+// classifyWords returns the kind of a statement from its unquoted words.
+func classifyWords(words []string) StatementKind {
+	if len(words) == 0 {
+		return WriteStatement
+	}
+
+	switch strings.ToUpper(words[0]) {
+	case "SELECT":
+		for _, w := range words[1:] {
+			if strings.EqualFold(w, "INTO") {
+				return WriteStatement
+			}
+		}
+		return ReadStatement
+	case "SHOW", "EXPLAIN":
+		return ReadStatement
+	case "DROP":
+		if len(words) > 1 {
+			switch strings.ToUpper(words[1]) {
+			case "SERIES", "MEASUREMENT":
+				return WriteStatement
+			}
+		}
+		return AdminStatement
+	case "CREATE", "ALTER", "GRANT", "REVOKE", "SET", "KILL":
+		return AdminStatement
+	default:
+		return WriteStatement
+	}
+}
+
+// influxqlToken is the kind of a token read by the influxqlScanner.
+type influxqlToken int
+
+const (
+	influxqlEOF influxqlToken = iota
+	influxqlWS
+	influxqlComment
+	influxqlIdent
+	influxqlQuotedIdent
+	influxqlString
+	influxqlNumber
+	influxqlRegex
+	influxqlSemicolon
+	influxqlOperator
+)
+
+// influxqlScanner is a lightweight InfluxQL tokenizer. It only recognizes
+// as much of the language as is needed to split and classify statements.
+type influxqlScanner struct {
+	s   string
+	pos int
+
+	// prev and prevLit are the last token that was not whitespace or a
+	// comment. They determine whether a slash starts a regular expression.
+	prev    influxqlToken
+	prevLit string
+
+	// source is set while the scanner is reading the sources of a FROM
+	// clause, where a regular expression may follow a qualifier.
+	source bool
+}
+
+// scan returns the next token, its literal text and its starting position.
+func (s *influxqlScanner) scan() (tok influxqlToken, lit string, pos int) {
+	pos = s.pos
+	tok = s.next()
+	lit = s.s[pos:s.pos]
+	if tok != influxqlWS && tok != influxqlComment {
+		s.source = s.inSource(tok, lit)
+		s.prev, s.prevLit = tok, lit
+	}
+	return tok, lit, pos
+}
+
+// inSource reports whether the token is part of the sources of a FROM
+// clause, such as "db"."rp"./cpu.*/ or cpu, mem.
+func (s *influxqlScanner) inSource(tok influxqlToken, lit string) bool {
+	switch tok {
+	case influxqlIdent:
+		if strings.EqualFold(lit, "FROM") {
+			return true
+		}
+		fallthrough
+	case influxqlQuotedIdent, influxqlRegex:
+		return s.source && (s.prev == influxqlIdent && strings.EqualFold(s.prevLit, "FROM") ||
+			s.prev == influxqlOperator && (s.prevLit == "." || s.prevLit == ","))
+	case influxqlOperator:
+		return s.source && (lit == "." || lit == ",")
+	}
+	return false
+}
+
+func (s *influxqlScanner) next() influxqlToken {
+	if s.pos >= len(s.s) {
+		return influxqlEOF
+	}
+
+	ch, size := utf8.DecodeRuneInString(s.s[s.pos:])
+	switch {
+	case unicode.IsSpace(ch):
+		s.pos += size
+		for s.pos < len(s.s) {
+			ch, size := utf8.DecodeRuneInString(s.s[s.pos:])
+			if !unicode.IsSpace(ch) {
+				break
+			}
+			s.pos += size
+		}
+		return influxqlWS
+	case isIdentChar(ch):
+		tok := influxqlIdent
+		if unicode.IsDigit(ch) {
+			tok = influxqlNumber
+		}
+		s.pos += size
+		s.skipIdent()
+		return tok
+	}
+
+	switch {
+	case strings.HasPrefix(s.s[s.pos:], "--"):
+		if i := strings.IndexByte(s.s[s.pos:], '\n'); i >= 0 {
+			s.pos += i + 1
+		} else {
+			s.pos = len(s.s)
+		}
+		return influxqlComment
+	case strings.HasPrefix(s.s[s.pos:], "/*"):
+		if i := strings.Index(s.s[s.pos+2:], "*/"); i >= 0 {
+			s.pos += i + 4
+		} else {
+			s.pos = len(s.s)
+		}
+		return influxqlComment
+	case strings.HasPrefix(s.s[s.pos:], "=~"), strings.HasPrefix(s.s[s.pos:], "!~"):
+		s.pos += 2
+		return influxqlOperator
+	}
+
+	s.pos += size
+	switch ch {
+	case ';':
+		return influxqlSemicolon
+	case '\'':
+		s.skipQuoted('\'')
+		return influxqlString
+	case '"':
+		s.skipQuoted('"')
+		return influxqlQuotedIdent
+	case '.':
+		if s.pos < len(s.s) && s.s[s.pos] >= '0' && s.s[s.pos] <= '9' {
+			s.skipIdent()
+			return influxqlNumber
+		}
+	case '/':
+		if s.regexAllowed() {
+			s.skipQuoted('/')
+			return influxqlRegex
+		}
+	}
+	return influxqlOperator
+}
+
+// regexAllowed reports whether a slash at the current position starts a
+// regular expression instead of being a division operator.
+func (s *influxqlScanner) regexAllowed() bool {
+	switch s.prev {
+	case influxqlOperator:
+		if s.prevLit == "." {
+			return s.source
+		}
+		return s.prevLit == "=~" || s.prevLit == "!~" || s.prevLit == ","
+	case influxqlIdent:
+		return strings.EqualFold(s.prevLit, "FROM") || strings.EqualFold(s.prevLit, "BY")
+	}
+	return false
+}
+
+// skipIdent skips the rest of an identifier, keyword, number or duration.
+// A dot that is followed by a slash is left for the regular expression of a
+// qualified source.
+func (s *influxqlScanner) skipIdent() {
+	for s.pos < len(s.s) {
+		ch, size := utf8.DecodeRuneInString(s.s[s.pos:])
+		if !isIdentChar(ch) && (ch != '.' || strings.HasPrefix(s.s[s.pos+1:], "/")) {
+			return
+		}
+		s.pos += size
+	}
+}
+
+// skipQuoted skips past the closing quote. A backslash escapes the
+// character after it. An unterminated quote runs to the end of the query.
+func (s *influxqlScanner) skipQuoted(quote byte) {
+	for s.pos < len(s.s) {
+		switch s.s[s.pos] {
+		case '\\':
+			s.pos += 2
+		case quote:
+			s.pos++
+			return
+		default:
+			s.pos++
+		}
+	}
+	s.pos = len(s.s)
+}
+
+func isIdentChar(ch rune) bool {
+	return unicode.IsLetter(ch) || unicode.IsDigit(ch) || ch == '_'
+}
@@ -0,0 +1,113 @@
+package influxdb_test
+
+import (
+	"reflect"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb-client"
+)
+
+func TestSplitStatements(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		have string
+		want []influxdb.QueryStatement
+	}{
+		{
+			name: "single",
+			have: "SELECT value FROM cpu",
+			want: []influxdb.QueryStatement{
+				{Text: "SELECT value FROM cpu", Kind: influxdb.ReadStatement},
+			},
+		},
+		{
+			name: "multiple",
+			have: " SELECT value FROM cpu ;\n\nCREATE DATABASE db0;DROP SERIES FROM cpu; ",
+			want: []influxdb.QueryStatement{
+				{Text: "SELECT value FROM cpu", Kind: influxdb.ReadStatement},
+				{Text: "CREATE DATABASE db0", Kind: influxdb.AdminStatement},
+				{Text: "DROP SERIES FROM cpu", Kind: influxdb.WriteStatement},
+			},
+		},
+		{
+			name: "quotes",
+			have: `SELECT "a;b" FROM cpu WHERE host = 'a;b' AND region = 'a\';b'; SHOW DATABASES`,
+			want: []influxdb.QueryStatement{
+				{Text: `SELECT "a;b" FROM cpu WHERE host = 'a;b' AND region = 'a\';b'`, Kind: influxdb.ReadStatement},
+				{Text: "SHOW DATABASES", Kind: influxdb.ReadStatement},
+			},
+		},
+		{
+			name: "comments",
+			have: "-- leading; comment\nSELECT value /* inline; comment */ FROM cpu -- trailing; comment\n; /* only a comment; */ ;",
+			want: []influxdb.QueryStatement{
+				{Text: "SELECT value /* inline; comment */ FROM cpu", Kind: influxdb.ReadStatement},
+			},
+		},
+		{
+			name: "regex",
+			have: `SELECT value FROM /cpu;\/[0-9]/ WHERE host =~ /a;b/ AND region !~ /c\/;d/; SELECT a / b FROM cpu, /mem;/`,
+			want: []influxdb.QueryStatement{
+				{Text: `SELECT value FROM /cpu;\/[0-9]/ WHERE host =~ /a;b/ AND region !~ /c\/;d/`, Kind: influxdb.ReadStatement},
+				{Text: "SELECT a / b FROM cpu, /mem;/", Kind: influxdb.ReadStatement},
+			},
+		},
+		{
+			name: "qualified regex",
+			have: `SELECT * FROM "db"."rp"./a;b/; SELECT * FROM db.rp./c;d/, "rp"./e;f/ WHERE x = 1./2; SHOW DATABASES`,
+			want: []influxdb.QueryStatement{
+				{Text: `SELECT * FROM "db"."rp"./a;b/`, Kind: influxdb.ReadStatement},
+				{Text: `SELECT * FROM db.rp./c;d/, "rp"./e;f/ WHERE x = 1./2`, Kind: influxdb.ReadStatement},
+				{Text: "SHOW DATABASES", Kind: influxdb.ReadStatement},
+			},
+		},
+		{
+			name: "group by regex",
+			have: `SELECT * FROM cpu GROUP BY /ho;st/; SELECT mean(value) FROM cpu GROUP BY time(1m), /a;b/ fill(none); SELECT 1`,
+			want: []influxdb.QueryStatement{
+				{Text: `SELECT * FROM cpu GROUP BY /ho;st/`, Kind: influxdb.ReadStatement},
+				{Text: `SELECT mean(value) FROM cpu GROUP BY time(1m), /a;b/ fill(none)`, Kind: influxdb.ReadStatement},
+				{Text: "SELECT 1", Kind: influxdb.ReadStatement},
+			},
+		},
+		{
+			name: "select into",
+			have: `SELECT value INTO cpu_copy FROM cpu; SELECT "into" FROM cpu WHERE host = 'into'`,
+			want: []influxdb.QueryStatement{
+				{Text: "SELECT value INTO cpu_copy FROM cpu", Kind: influxdb.WriteStatement},
+				{Text: `SELECT "into" FROM cpu WHERE host = 'into'`, Kind: influxdb.ReadStatement},
+			},
+		},
+		{
+			name: "empty",
+			have: " ; -- nothing\n",
+		},
+	} {
+		if got := influxdb.SplitStatements(tt.have); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %#v; want %#v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyStatement(t *testing.T) {
+	for _, tt := range []struct {
+		have string
+		want influxdb.StatementKind
+	}{
+		{have: "select * from cpu", want: influxdb.ReadStatement},
+		{have: "SHOW MEASUREMENTS WITH MEASUREMENT =~ /cpu/", want: influxdb.ReadStatement},
+		{have: "EXPLAIN ANALYZE SELECT * FROM cpu", want: influxdb.ReadStatement},
+		{have: "SELECT * INTO cpu_copy FROM cpu", want: influxdb.WriteStatement},
+		{have: "DELETE FROM cpu WHERE time < now() - 1d", want: influxdb.WriteStatement},
+		{have: "DROP MEASUREMENT cpu", want: influxdb.WriteStatement},
+		{have: "DROP DATABASE db0", want: influxdb.AdminStatement},
+		{have: "CREATE RETENTION POLICY rp0 ON db0 DURATION 1d REPLICATION 1", want: influxdb.AdminStatement},
+		{have: "GRANT ALL TO admin", want: influxdb.AdminStatement},
+		{have: "KILL QUERY 36", want: influxdb.AdminStatement},
+		{have: "BOGUS", want: influxdb.WriteStatement},
+	} {
+		if got := influxdb.ClassifyStatement(tt.have); got != tt.want {
+			t.Errorf("%q: got %s; want %s", tt.have, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package influxdb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Operation identifies the kind of request the client is making.
+type Operation string
+
+const (
+	// OperationQuery is a request to the /query endpoint.
+	OperationQuery = Operation("query")
+
+	// OperationWrite is a request to the /write endpoint.
+	OperationWrite = Operation("write")
+
+	// OperationPing is a request to the /ping endpoint.
+	OperationPing = Operation("ping")
+)
+
+func (op Operation) String() string {
+	return string(op)
+}
+
+// RequestHandler sends a request and returns the response.
+type RequestHandler func(req *http.Request) (*http.Response, error)
+
+// Interceptor wraps every request made by a Client. An Interceptor can
+// inspect or modify the request before calling next and inspect or modify
+// the response after next returns. It can also short-circuit the request by
+// returning a response or an error without calling next. An Interceptor
+// must return either a non-nil response or an error.
+type Interceptor func(req *http.Request, next RequestHandler) (*http.Response, error)
+
+type operationKey struct{}
+
+// RequestOperation returns the Operation of a request made by a Client. This
+// can be used by an Interceptor to decide how to handle a request. An empty
+// Operation is returned if the request was not made by a Client.
+func RequestOperation(req *http.Request) Operation {
+	op, _ := req.Context().Value(operationKey{}).(Operation)
+	return op
+}
+
+// do sends the request through the chain of interceptors. The first
+// interceptor in the chain is the outermost one.
+func (c *Client) do(op Operation, req *http.Request) (*http.Response, error) {
+	req = req.WithContext(context.WithValue(req.Context(), operationKey{}, op))
+
+	handler := RequestHandler(c.Client.Do)
+	for i := len(c.Interceptors) - 1; i >= 0; i-- {
+		interceptor, next := c.Interceptors[i], handler
+		handler = func(req *http.Request) (*http.Response, error) {
+			return interceptor(req, next)
+		}
+	}
+
+	start := time.Now()
+	resp, err := handler(req)
+	if resp == nil && err == nil {
+		err = errors.New("interceptor returned neither a response nor an error")
+	}
+	c.statistics().recordRequest(op, resp, err, time.Since(start))
+	return resp, err
+}
@@ -0,0 +1,114 @@
+package influxdb_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb-client"
+)
+
+func TestClient_Interceptors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("X-Trace-Id"), "trace0"; got != want {
+			t.Errorf("X-Trace-Id = %q; want %q", got, want)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, err := influxdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls []string
+	client.Interceptors = []influxdb.Interceptor{
+		func(req *http.Request, next influxdb.RequestHandler) (*http.Response, error) {
+			calls = append(calls, "outer:"+influxdb.RequestOperation(req).String())
+			resp, err := next(req)
+			calls = append(calls, "outer:done")
+			return resp, err
+		},
+		func(req *http.Request, next influxdb.RequestHandler) (*http.Response, error) {
+			calls = append(calls, "inner:"+influxdb.RequestOperation(req).String())
+			req.Header.Set("X-Trace-Id", "trace0")
+			return next(req)
+		},
+	}
+
+	if _, err := client.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Writer().Write([]byte("cpu value=5\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"outer:ping", "inner:ping", "outer:done",
+		"outer:write", "inner:write", "outer:done",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("unexpected calls: have=%#v want=%#v", calls, want)
+	}
+}
+
+func TestClient_Interceptors_ShortCircuit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request should not have reached the server")
+	}))
+	defer server.Close()
+
+	client, err := influxdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errInjected := errors.New("injected failure")
+	client.Interceptors = []influxdb.Interceptor{
+		func(req *http.Request, next influxdb.RequestHandler) (*http.Response, error) {
+			switch influxdb.RequestOperation(req) {
+			case influxdb.OperationQuery:
+				return &http.Response{
+					StatusCode: http.StatusServiceUnavailable,
+					Status:     "503 Service Unavailable",
+					Header:     http.Header{},
+					Body:       ioutil.NopCloser(strings.NewReader("")),
+					Request:    req,
+				}, nil
+			default:
+				return nil, errInjected
+			}
+		},
+	}
+
+	if _, err := client.Writer().Write([]byte("cpu value=5\n")); err != errInjected {
+		t.Errorf("unexpected error: have=%v want=%v", err, errInjected)
+	}
+	if err := client.Execute("SHOW DATABASES"); err == nil {
+		t.Error("expected error")
+	} else if have, want := err.Error(), "unknown http error: 503 Service Unavailable"; have != want {
+		t.Errorf("unexpected error: have=%#v want=%#v", have, want)
+	}
+}
+
+func TestClient_Interceptors_NoResponse(t *testing.T) {
+	client := &influxdb.Client{
+		Interceptors: []influxdb.Interceptor{
+			func(req *http.Request, next influxdb.RequestHandler) (*http.Response, error) {
+				return nil, nil
+			},
+		},
+	}
+
+	if _, err := client.Ping(); err == nil {
+		t.Fatal("expected error")
+	}
+	if have, want := client.Stats().Pings.Errors, int64(1); have != want {
+		t.Fatalf("unexpected ping errors: have=%d want=%d", have, want)
+	}
+}
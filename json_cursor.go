@@ -4,25 +4,61 @@ import (
 	"encoding/json"
 	"io"
 	"sort"
+	"strconv"
 	"time"
 )
 
 type jsonCursor struct {
-	r   io.ReadCloser
-	dec *json.Decoder
+	r          io.ReadCloser
+	stream     *jsonStream
+	epoch      time.Duration
+	statements []string
+	index      int
 
 	cur *jsonResult
-	buf struct {
-		Results []*jsonResult `json:"results"`
-		Error   string        `json:"error"`
-	}
+	buf jsonResponse
+}
+
+// jsonResponse holds the results read from the stream that have not been
+// returned yet.
+type jsonResponse struct {
+	Results []*jsonResult
+	Error   string
 }
 
-func newJSONCursor(r io.ReadCloser) *jsonCursor {
+func newJSONCursor(r io.ReadCloser, opt CursorOptions) *jsonCursor {
+	dec := json.NewDecoder(r)
+	if !opt.FloatNumbers {
+		dec.UseNumber()
+	}
 	return &jsonCursor{
-		r:   r,
-		dec: json.NewDecoder(r),
+		r:          r,
+		stream:     newJSONStream(dec, opt.MaxBufferedRows),
+		epoch:      opt.Epoch.Duration(),
+		statements: opt.Statements,
+	}
+}
+
+// statement returns the text of the statement that produced the result. The
+// statement is found by the statement id sent by the server. If the server
+// did not send one, the results are assumed to be in the same order as the
+// statements.
+// If the server did not send a statement id, it is set on the result.
+func (c *jsonCursor) statement(result *jsonResult) string {
+	if result.statementID < 0 {
+		result.statementID = c.index
 	}
+	c.index = result.statementID + 1
+
+	if id := result.statementID; id < len(c.statements) {
+		return c.statements[id]
+	}
+	return ""
+}
+
+// decode reads the next result from the stream into the results buffer.
+func (c *jsonCursor) decode() error {
+	return c.stream.next(&c.buf)
 }
 
 func (c *jsonCursor) NextSet() (*ResultSet, error) {
@@ -36,7 +72,7 @@ func (c *jsonCursor) NextSet() (*ResultSet, error) {
 		// not marked partial.
 		for c.cur.Partial {
 			for len(c.buf.Results) == 0 {
-				if err := c.dec.Decode(&c.buf); err != nil {
+				if err := c.decode(); err != nil {
 					if err == io.EOF {
 						err = io.ErrUnexpectedEOF
 					}
@@ -55,22 +91,29 @@ func (c *jsonCursor) NextSet() (*ResultSet, error) {
 
 	// Fill the results buffer with results until we have something.
 	for len(c.buf.Results) == 0 {
-		if err := c.dec.Decode(&c.buf); err != nil {
+		if err := c.decode(); err != nil {
 			return nil, err
 		} else if c.buf.Error != "" {
-			return nil, ErrResult{Err: c.buf.Error}
+			return nil, ErrResult{Err: c.buf.Error, StatementID: -1}
 		}
 	}
 
 	// Keep track of the currently active ResultSet so we can later invalidate
 	// it if we need to.
 	c.cur = c.buf.Results[0]
+	c.cur.statement = c.statement(c.cur)
 	if c.cur.Error != "" {
-		// Return an error instead of the ResultSet if the result contained an error.
-		return nil, ErrResult{Err: c.cur.Error}
+		// Return an error instead of the ResultSet if the result contained
+		// an error. The result is discarded so the next call continues with
+		// the next statement.
+		err := c.cur.err()
+		c.buf.Results = c.buf.Results[1:]
+		c.cur = nil
+		return nil, err
 	}
 
 	c.buf.Results = c.buf.Results[1:]
+	c.cur.epoch = c.epoch
 	if c.cur.Partial {
 		c.cur.cur = c
 	}
@@ -84,7 +127,11 @@ func (c *jsonCursor) NextSet() (*ResultSet, error) {
 }
 
 func (c *jsonCursor) Close() error {
-	if err := c.r.Close(); err != nil {
+	// Close the reader before stopping the stream so a read that is blocked
+	// in another goroutine fails instead of waiting for more data.
+	err := c.r.Close()
+	c.stream.close()
+	if err != nil {
 		return err
 	}
 	c.buf.Results = nil
@@ -96,24 +143,40 @@ func (c *jsonCursor) Hijack() (io.ReadCloser, error) {
 }
 
 type jsonResult struct {
-	Series []struct {
-		Name    string            `json:"name"`
-		Tags    map[string]string `json:"tags"`
-		Columns []string          `json:"columns"`
-		Values  [][]interface{}   `json:"values"`
-		Partial bool              `json:"partial"`
-	} `json:"series"`
-	MessageList []*Message `json:"messages"`
-	Partial     bool       `json:"partial"`
-	Error       string     `json:"error"`
+	Series      []jsonSeriesData
+	MessageList []*Message
+	Partial     bool
+	Error       string
 
 	index         int
+	epoch         time.Duration
+	statementID   int
+	statement     string
 	columns       []string
 	columnsByName map[string]int
 	cur           *jsonCursor
 	series        *jsonSeries
 }
 
+// jsonSeriesData is a series, or part of a series, read from the stream.
+type jsonSeriesData struct {
+	Name    string
+	Tags    map[string]string
+	Columns []string
+	Values  [][]interface{}
+	Partial bool
+}
+
+// continueWith copies the state of the next chunk of a partial result into
+// the result. We must use the same result struct so that we keep all
+// references.
+func (r *jsonResult) continueWith(result *jsonResult) {
+	r.Series = result.Series
+	r.Partial = result.Partial
+	r.MessageList = append(r.MessageList, result.MessageList...)
+	r.index = 0
+}
+
 // Columns returns the columns for this result.
 //
 // Columns is just a gigantic mistake in the JSON output for InfluxDB. Columns
@@ -155,6 +218,24 @@ func (r *jsonResult) Index(name string) int {
 	return -1
 }
 
+func (r *jsonResult) StatementID() int {
+	return r.statementID
+}
+
+func (r *jsonResult) Statement() string {
+	return r.statement
+}
+
+// err returns the error in the result.
+func (r *jsonResult) err() ErrResult {
+	return r.errorFrom(r)
+}
+
+// errorFrom returns the error in a chunk of the result.
+func (r *jsonResult) errorFrom(chunk *jsonResult) ErrResult {
+	return ErrResult{Err: chunk.Error, StatementID: r.statementID, Statement: r.statement}
+}
+
 func (r *jsonResult) Messages() []*Message {
 	return r.MessageList
 }
@@ -184,7 +265,7 @@ func (r *jsonResult) NextSeries() (*Series, error) {
 
 					// Fill the results buffer with results until we have something.
 					for len(r.cur.buf.Results) == 0 {
-						if err := r.cur.dec.Decode(&r.cur.buf); err != nil {
+						if err := r.cur.decode(); err != nil {
 							if err == io.EOF {
 								err = io.ErrUnexpectedEOF
 							}
@@ -192,17 +273,13 @@ func (r *jsonResult) NextSeries() (*Series, error) {
 						}
 					}
 
-					// Copy the state of the next result into the current
-					// result. We must use the same result struct so that we
-					// keep all references.
+					// Copy the state of the next result into the current result.
 					result := r.cur.buf.Results[0]
 					if result.Error != "" {
-						return nil, ErrResult{Err: result.Error}
+						return nil, r.errorFrom(result)
 					}
 					r.cur.buf.Results = r.cur.buf.Results[1:]
-					r.Series = result.Series
-					r.Partial = result.Partial
-					r.index = 0
+					r.continueWith(result)
 				}
 
 				if !r.Series[r.index].Partial {
@@ -236,7 +313,7 @@ func (r *jsonResult) NextSeries() (*Series, error) {
 
 		// Fill the results buffer with results until we have something.
 		for len(r.cur.buf.Results) == 0 {
-			if err := r.cur.dec.Decode(&r.cur.buf); err != nil {
+			if err := r.cur.decode(); err != nil {
 				if err == io.EOF {
 					err = io.ErrUnexpectedEOF
 				}
@@ -244,17 +321,13 @@ func (r *jsonResult) NextSeries() (*Series, error) {
 			}
 		}
 
-		// Copy the state of the next result into the current
-		// result. We must use the same result struct so that we
-		// keep all references.
+		// Copy the state of the next result into the current result.
 		result := r.cur.buf.Results[0]
 		if result.Error != "" {
-			return nil, ErrResult{Err: result.Error}
+			return nil, r.errorFrom(result)
 		}
 		r.cur.buf.Results = r.cur.buf.Results[1:]
-		r.Series = result.Series
-		r.Partial = result.Partial
-		r.index = 0
+		r.continueWith(result)
 	}
 
 	// Retrieve the index of the next series and initialize the series.
@@ -328,24 +401,22 @@ func (s *jsonSeries) NextRow() (Row, error) {
 			}
 
 			// Fill the results buffer with results until we have something.
-			if err := s.r.cur.dec.Decode(&s.r.cur.buf); err != nil {
-				if err == io.EOF {
-					err = io.ErrUnexpectedEOF
+			for len(s.r.cur.buf.Results) == 0 {
+				if err := s.r.cur.decode(); err != nil {
+					if err == io.EOF {
+						err = io.ErrUnexpectedEOF
+					}
+					return nil, err
 				}
-				return nil, err
 			}
 
-			// Copy the state of the next result into the current
-			// result. We must use the same result struct so that we
-			// keep all references.
+			// Copy the state of the next result into the current result.
 			result := s.r.cur.buf.Results[0]
 			if result.Error != "" {
-				return nil, ErrResult{Err: result.Error}
+				return nil, s.r.errorFrom(result)
 			}
 			s.r.cur.buf.Results = s.r.cur.buf.Results[1:]
-			s.r.Series = result.Series
-			s.r.Partial = result.Partial
-			s.r.index = 0
+			s.r.continueWith(result)
 		}
 
 		v := s.r.Series[s.r.index]
@@ -358,9 +429,31 @@ func (s *jsonSeries) NextRow() (Row, error) {
 
 	v := s.values[0]
 	s.values = s.values[1:]
+	convertNumbers(v)
 	return jsonRow{values: v, result: s.r}, nil
 }
 
+// convertNumbers replaces any json.Number in the values with an int64 if the
+// number is an integer that fits, a uint64 if it is a larger integer, or a
+// float64 otherwise.
+func convertNumbers(values []interface{}) {
+	for i, v := range values {
+		n, ok := v.(json.Number)
+		if !ok {
+			continue
+		}
+
+		if v, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+			values[i] = v
+		} else if v, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+			values[i] = v
+		} else {
+			v, _ := strconv.ParseFloat(string(n), 64)
+			values[i] = v
+		}
+	}
+}
+
 type jsonRow struct {
 	values []interface{}
 	result *jsonResult
@@ -369,26 +462,10 @@ type jsonRow struct {
 func (r jsonRow) Time() time.Time {
 	// Retrieve the value for the time column if it exists. This is usually the
 	// first column so this should be pretty fast. Column indexing is also
-	// shared between rows.
-	v := r.ValueByName("time")
-	if v == nil {
-		return time.Time{}
-	}
-
-	// Attempt to cast this to a string or float64. The time column can be
-	// either one of those two values. It will either be the number of
-	// nanoseconds since the epoch or a string in RFC3339Nano format.
-	switch v := v.(type) {
-	case string:
-		// Parse the time using RFC3339Nano. This also accepts RFC3339 without
-		// nanoseconds. If it doesn't parse, then the time column does not contain
-		// a time value.
-		t, _ := time.Parse(time.RFC3339Nano, v)
-		return t
-	case float64:
-		return time.Unix(0, int64(v)).UTC()
-	}
-	return time.Time{}
+	// shared between rows. If the time column does not contain a time value,
+	// this returns the zero time.
+	t, _ := r.TimeByName("time")
+	return t
 }
 
 func (r jsonRow) Value(index int) interface{} {
@@ -406,3 +483,95 @@ func (r jsonRow) ValueByName(column string) interface{} {
 	}
 	return r.values[index]
 }
+
+// lookup returns the value and the column name at index.
+func (r jsonRow) lookup(index int) (interface{}, string, error) {
+	if index < 0 || index >= len(r.values) {
+		return nil, "", ErrNoColumn{Index: index}
+	}
+
+	var column string
+	if index < len(r.result.columns) {
+		column = r.result.columns[index]
+	}
+	return r.values[index], column, nil
+}
+
+// lookupByName returns the value of the named column.
+func (r jsonRow) lookupByName(column string) (interface{}, string, error) {
+	index := r.result.Index(column)
+	if index < 0 || index >= len(r.values) {
+		return nil, column, ErrNoColumn{Column: column, Index: -1}
+	}
+	return r.values[index], column, nil
+}
+
+func (r jsonRow) Float(index int) (float64, error) {
+	v, column, err := r.lookup(index)
+	return convertColumn(v, column, err, "float64", asFloat)
+}
+
+func (r jsonRow) FloatByName(column string) (float64, error) {
+	v, column, err := r.lookupByName(column)
+	return convertColumn(v, column, err, "float64", asFloat)
+}
+
+func (r jsonRow) Int(index int) (int64, error) {
+	v, column, err := r.lookup(index)
+	return convertColumn(v, column, err, "int64", asInt)
+}
+
+func (r jsonRow) IntByName(column string) (int64, error) {
+	v, column, err := r.lookupByName(column)
+	return convertColumn(v, column, err, "int64", asInt)
+}
+
+func (r jsonRow) Uint(index int) (uint64, error) {
+	v, column, err := r.lookup(index)
+	return convertColumn(v, column, err, "uint64", asUint)
+}
+
+func (r jsonRow) UintByName(column string) (uint64, error) {
+	v, column, err := r.lookupByName(column)
+	return convertColumn(v, column, err, "uint64", asUint)
+}
+
+func (r jsonRow) String(index int) (string, error) {
+	v, column, err := r.lookup(index)
+	return convertColumn(v, column, err, "string", asString)
+}
+
+func (r jsonRow) StringByName(column string) (string, error) {
+	v, column, err := r.lookupByName(column)
+	return convertColumn(v, column, err, "string", asString)
+}
+
+func (r jsonRow) Bool(index int) (bool, error) {
+	v, column, err := r.lookup(index)
+	return convertColumn(v, column, err, "bool", asBool)
+}
+
+func (r jsonRow) BoolByName(column string) (bool, error) {
+	v, column, err := r.lookupByName(column)
+	return convertColumn(v, column, err, "bool", asBool)
+}
+
+func (r jsonRow) TimeAt(index int) (time.Time, error) {
+	v, column, err := r.lookup(index)
+	return convertColumn(v, column, err, "time.Time", asTime(r.result.epoch))
+}
+
+func (r jsonRow) TimeByName(column string) (time.Time, error) {
+	v, column, err := r.lookupByName(column)
+	return convertColumn(v, column, err, "time.Time", asTime(r.result.epoch))
+}
+
+func (r jsonRow) Duration(index int) (time.Duration, error) {
+	v, column, err := r.lookup(index)
+	return convertColumn(v, column, err, "time.Duration", asDuration)
+}
+
+func (r jsonRow) DurationByName(column string) (time.Duration, error) {
+	v, column, err := r.lookupByName(column)
+	return convertColumn(v, column, err, "time.Duration", asDuration)
+}
@@ -1,6 +1,7 @@
 package influxdb_test
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -48,13 +49,13 @@ func TestCursor_JSON_Basic(t *testing.T) {
 
 	if got, err := series.NextRow(); err != nil {
 		t.Fatalf("unexpected err: %v", err)
-	} else if want := []interface{}{"2010-01-01T00:00:00Z", float64(2)}; !reflect.DeepEqual(got.Values(), want) {
+	} else if want := []interface{}{"2010-01-01T00:00:00Z", int64(2)}; !reflect.DeepEqual(got.Values(), want) {
 		t.Fatalf("got %#v; want %#v", got.Values(), want)
 	}
 
 	if got, err := series.NextRow(); err != nil {
 		t.Fatalf("unexpected err: %v", err)
-	} else if want := []interface{}{"2010-01-01T00:00:10Z", float64(3)}; !reflect.DeepEqual(got.Values(), want) {
+	} else if want := []interface{}{"2010-01-01T00:00:10Z", int64(3)}; !reflect.DeepEqual(got.Values(), want) {
 		t.Fatalf("got %#v; want %#v", got.Values(), want)
 	}
 
@@ -99,7 +100,7 @@ func TestCursor_JSON_ResultError(t *testing.T) {
 
 	if got, err := series.NextRow(); err != nil {
 		t.Fatalf("unexpected err: %v", err)
-	} else if want := []interface{}{"2010-01-01T00:00:00Z", float64(2)}; !reflect.DeepEqual(got.Values(), want) {
+	} else if want := []interface{}{"2010-01-01T00:00:00Z", int64(2)}; !reflect.DeepEqual(got.Values(), want) {
 		t.Fatalf("got %#v; want %#v", got.Values(), want)
 	}
 
@@ -164,15 +165,315 @@ func TestCursor_JSON_Row(t *testing.T) {
 		t.Fatalf("got %#v; want %#v", got, want)
 	}
 
-	if got, want := row.ValueByName("value"), float64(2); got != want {
+	if got, want := row.ValueByName("value"), int64(2); got != want {
 		t.Fatalf("got %#v; want %#v", got, want)
 	}
 
-	if got, want := row.Value(1), float64(2); got != want {
+	if got, want := row.Value(1), int64(2); got != want {
 		t.Fatalf("got %#v; want %#v", got, want)
 	}
 }
 
+func TestCursor_JSON_Numbers(t *testing.T) {
+	const data = `{"results":[{"series":[{"name":"cpu","columns":["time","counter","max","value"],"values":[[1262304000000000001,9007199254740993,18446744073709551615,2.5]]}]}]}`
+
+	for _, tt := range []struct {
+		opt  influxdb.CursorOptions
+		want []interface{}
+	}{
+		{
+			want: []interface{}{int64(1262304000000000001), int64(9007199254740993), uint64(18446744073709551615), float64(2.5)},
+		},
+		{
+			opt:  influxdb.CursorOptions{FloatNumbers: true},
+			want: []interface{}{float64(1262304000000000001), float64(9007199254740993), float64(18446744073709551615), float64(2.5)},
+		},
+	} {
+		cur, err := influxdb.NewCursorOptions(ioutil.NopCloser(strings.NewReader(data)), "json", tt.opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := cur.NextSet()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		series, err := result.NextSeries()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		row, err := series.NextRow()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if got := row.Values(); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("got %#v; want %#v", got, tt.want)
+		}
+		if !tt.opt.FloatNumbers {
+			if got, want := row.Time(), time.Unix(1262304000, 1).UTC(); got != want {
+				t.Fatalf("got %#v; want %#v", got, want)
+			}
+		}
+	}
+}
+
+func TestCursor_JSON_TypedAccessors(t *testing.T) {
+	r := strings.NewReader(`{"results":[{"series":[{"name":"cpu","columns":["time","value","count","host","up","duration","missing"],"values":[[1262304000000,2.5,18446744073709551615,"server01",true,"1h30m0s",null]]}]}]}`)
+	cur, err := influxdb.NewCursorOptions(ioutil.NopCloser(r), "json", influxdb.CursorOptions{Epoch: influxdb.PrecisionMillisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := cur.NextSet()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	series, err := result.NextSeries()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	row, err := series.NextRow()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, err := row.TimeAt(0); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if want := mustParseTime("2010-01-01T00:00:00Z"); !got.Equal(want) {
+		t.Errorf("TimeAt = %v; want %v", got, want)
+	}
+	if got, err := row.Float(1); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if want := 2.5; got != want {
+		t.Errorf("Float = %v; want %v", got, want)
+	}
+	if got, err := row.FloatByName("time"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if want := float64(1262304000000); got != want {
+		t.Errorf("FloatByName = %v; want %v", got, want)
+	}
+	if got, err := row.UintByName("count"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if want := uint64(18446744073709551615); got != want {
+		t.Errorf("UintByName = %v; want %v", got, want)
+	}
+	if got, err := row.IntByName("time"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if want := int64(1262304000000); got != want {
+		t.Errorf("IntByName = %v; want %v", got, want)
+	}
+	if got, err := row.StringByName("host"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if want := "server01"; got != want {
+		t.Errorf("StringByName = %q; want %q", got, want)
+	}
+	if got, err := row.Bool(4); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if !got {
+		t.Errorf("Bool = %v; want %v", got, true)
+	}
+	if got, err := row.DurationByName("duration"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if want := 90 * time.Minute; got != want {
+		t.Errorf("DurationByName = %v; want %v", got, want)
+	}
+
+	for _, tt := range []struct {
+		name string
+		have error
+		want error
+	}{
+		{name: "int overflow", have: second(row.IntByName("count")), want: influxdb.ErrColumnType{Column: "count", Type: "int64", Value: uint64(18446744073709551615)}},
+		{name: "int from float", have: second(row.Int(1)), want: influxdb.ErrColumnType{Column: "value", Type: "int64", Value: 2.5}},
+		{name: "string mismatch", have: second(row.String(4)), want: influxdb.ErrColumnType{Column: "up", Type: "string", Value: true}},
+		{name: "null", have: second(row.FloatByName("missing")), want: influxdb.ErrNullValue{Column: "missing"}},
+		{name: "no column", have: second(row.FloatByName("mean")), want: influxdb.ErrNoColumn{Column: "mean", Index: -1}},
+		{name: "index out of range", have: second(row.Float(7)), want: influxdb.ErrNoColumn{Index: 7}},
+	} {
+		if tt.have != tt.want {
+			t.Errorf("%s: got error %#v; want %#v", tt.name, tt.have, tt.want)
+		}
+	}
+
+	if v, ok, err := influxdb.Nullable(row.FloatByName("missing")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if ok || v != 0 {
+		t.Errorf("Nullable = (%v, %v); want (0, false)", v, ok)
+	}
+	if v, ok, err := influxdb.Nullable(row.FloatByName("value")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if !ok || v != 2.5 {
+		t.Errorf("Nullable = (%v, %v); want (2.5, true)", v, ok)
+	}
+	if _, _, err := influxdb.Nullable(row.FloatByName("mean")); err == nil {
+		t.Error("expected error for a missing column")
+	}
+}
+
+func TestCursor_JSON_MaxBufferedRows(t *testing.T) {
+	r := strings.NewReader(`{"results":[` +
+		`{"series":[{"name":"cpu","columns":["time","value"],"values":[[0,1],[1,2],[2,3],[3,4],[4,5]]},{"name":"mem","columns":["time","value"],"values":[[0,6]]}],"messages":[{"level":"warning","text":"deprecated"}]},` +
+		`{"series":[{"name":"disk","columns":["time","value"],"values":[[0,7],[1,8],[2,9]]}]}` +
+		`]}`)
+	cur, err := influxdb.NewCursorOptions(ioutil.NopCloser(r), "json", influxdb.CursorOptions{MaxBufferedRows: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := cur.NextSet()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	series, err := result.NextSeries()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n, complete := series.Len(); n != 2 || complete {
+		t.Fatalf("Len = (%d, %v); want (2, false)", n, complete)
+	}
+
+	var got []interface{}
+	series.Each(func(row influxdb.Row) error {
+		got = append(got, row.Value(1))
+		return nil
+	})
+	if want := []interface{}{int64(1), int64(2), int64(3), int64(4), int64(5)}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v; want %#v", got, want)
+	}
+	if n, complete := series.Len(); n != 5 || !complete {
+		t.Fatalf("Len = (%d, %v); want (5, true)", n, complete)
+	}
+
+	if series, err := result.NextSeries(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if got, want := series.Name(), "mem"; got != want {
+		t.Fatalf("got %#v; want %#v", got, want)
+	}
+	if _, err := result.NextSeries(); err != io.EOF {
+		t.Fatalf("expected %v, got %v", io.EOF, err)
+	}
+	if got, want := result.Messages(), []*influxdb.Message{{Level: "warning", Text: "deprecated"}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v; want %#v", got, want)
+	}
+
+	// Skip the rest of the next result after reading a single row.
+	result, err = cur.NextSet()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	series, err = result.NextSeries()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if row, err := series.NextRow(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if got, want := row.Value(1), int64(7); got != want {
+		t.Fatalf("got %#v; want %#v", got, want)
+	}
+	if _, err := cur.NextSet(); err != io.EOF {
+		t.Fatalf("expected %v, got %v", io.EOF, err)
+	}
+}
+
+func TestCursor_JSON_Streaming(t *testing.T) {
+	// The response is cut off after many rows. The first rows should be
+	// returned without reading the entire response.
+	var buf strings.Builder
+	buf.WriteString(`{"results":[{"series":[{"name":"cpu","columns":["time","value"],"values":[`)
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		fmt.Fprintf(&buf, "[%d,%d]", i, i)
+	}
+	r := io.MultiReader(strings.NewReader(buf.String()), errorReader{})
+
+	cur, err := influxdb.NewCursorOptions(ioutil.NopCloser(r), "json", influxdb.CursorOptions{MaxBufferedRows: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := cur.NextSet()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	series, err := result.NextSeries()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	n := 0
+	err = series.Each(func(row influxdb.Row) error {
+		n++
+		return nil
+	})
+	if err != errStreamFailed {
+		t.Fatalf("got error %#v; want %#v", err, errStreamFailed)
+	} else if n < 990 {
+		t.Fatalf("got %d rows before the error; want at least 990", n)
+	}
+}
+
+func TestCursor_JSON_ConcurrentClose(t *testing.T) {
+	// The response stops in the middle of a result and waits for more data.
+	pr, pw := io.Pipe()
+	go io.WriteString(pw, `{"results":[{"series":[{"name":"cpu","columns":["time","value"],"values":[[0,1]`)
+
+	cur, err := influxdb.NewCursor(pr, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := cur.NextSet()
+		errs <- err
+	}()
+
+	// Give NextSet time to block on the read before closing the cursor.
+	time.Sleep(10 * time.Millisecond)
+	closed := make(chan error, 1)
+	go func() { closed <- cur.Close() }()
+
+	timer := time.NewTimer(time.Second)
+	defer timer.Stop()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	case <-timer.C:
+		t.Fatal("Close did not return")
+	}
+
+	select {
+	case err := <-errs:
+		if err != io.ErrClosedPipe {
+			t.Fatalf("got error %#v; want %#v", err, io.ErrClosedPipe)
+		}
+	case <-timer.C:
+		t.Fatal("NextSet did not return")
+	}
+}
+
+var errStreamFailed = errors.New("stream failed")
+
+// errorReader always fails to read.
+type errorReader struct{}
+
+func (errorReader) Read(p []byte) (int, error) {
+	return 0, errStreamFailed
+}
+
+// second returns the error from the result of a typed accessor.
+func second[T any](_ T, err error) error {
+	return err
+}
+
 func mustParseTime(s string) time.Time {
 	t, err := time.Parse(time.RFC3339Nano, s)
 	if err != nil {
@@ -0,0 +1,350 @@
+package influxdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"sync"
+)
+
+// DefaultMaxBufferedRows is the default number of rows a Cursor will hold in
+// memory before yielding them.
+const DefaultMaxBufferedRows = 1000
+
+// jsonStream decodes the JSON responses from the server one token at a time.
+// Instead of decoding an entire response at once, it yields each result as
+// soon as it has been read. A result with more rows than the limit is cut
+// into multiple chunks in the same way the server does for a chunked
+// response so the memory held is bounded by the size of a chunk. The chunks
+// are marked as partial so the cursor stitches them back together.
+type jsonStream struct {
+	dec     *json.Decoder
+	maxRows int
+	err     error
+	done    bool
+
+	// mu serializes pulling from the stream and stopping it, which can
+	// happen in different goroutines when a Cursor is closed while it is
+	// reading.
+	mu   sync.Mutex
+	pull func() (jsonChunk, bool)
+	stop func()
+}
+
+// jsonChunk is a single result, or part of a result, read from the stream.
+// If err is set, the response contained an error instead of a result.
+type jsonChunk struct {
+	result *jsonResult
+	err    string
+}
+
+func newJSONStream(dec *json.Decoder, maxRows int) *jsonStream {
+	if maxRows <= 0 {
+		maxRows = DefaultMaxBufferedRows
+	}
+	s := &jsonStream{dec: dec, maxRows: maxRows}
+	s.pull, s.stop = iter.Pull(s.parse)
+	return s
+}
+
+// next reads the next chunk from the stream into buf. It returns io.EOF when
+// there are no more responses in the stream and io.ErrUnexpectedEOF if the
+// stream was closed before it was completely read.
+func (s *jsonStream) next(buf *jsonResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chunk, ok := s.pull()
+	if !ok {
+		if s.err != nil {
+			return s.err
+		} else if !s.done {
+			return io.ErrUnexpectedEOF
+		}
+		return io.EOF
+	}
+
+	buf.Results, buf.Error = nil, chunk.err
+	if chunk.result != nil {
+		buf.Results = []*jsonResult{chunk.result}
+	}
+	return nil
+}
+
+// close stops the stream from reading any more data. If another goroutine is
+// reading from the stream, close waits for that read to finish so the
+// underlying reader should be closed first.
+func (s *jsonStream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stop()
+}
+
+// parse reads every response from the stream and yields each chunk.
+func (s *jsonStream) parse(yield func(jsonChunk) bool) {
+	for {
+		tok, err := s.dec.Token()
+		if err == io.EOF {
+			s.done = true
+			return
+		} else if err != nil {
+			s.err = err
+			return
+		} else if tok != json.Delim('{') {
+			s.err = fmt.Errorf("json: expected %v, got %v", json.Delim('{'), tok)
+			return
+		}
+
+		if !s.parseResponse(yield) {
+			return
+		}
+	}
+}
+
+// parseResponse reads a single response after its opening brace.
+func (s *jsonStream) parseResponse(yield func(jsonChunk) bool) bool {
+	var errmsg string
+	for s.dec.More() {
+		key, ok := s.key()
+		if !ok {
+			return false
+		}
+
+		switch key {
+		case "results":
+			if !s.expect(json.Delim('[')) {
+				return false
+			}
+			for s.dec.More() {
+				if !s.expect(json.Delim('{')) || !s.parseResult(yield) {
+					return false
+				}
+			}
+			if !s.expect(json.Delim(']')) {
+				return false
+			}
+		case "error":
+			if !s.decode(&errmsg) {
+				return false
+			}
+		default:
+			if !s.skip() {
+				return false
+			}
+		}
+	}
+
+	if !s.expect(json.Delim('}')) {
+		return false
+	}
+	if errmsg != "" {
+		return yield(jsonChunk{err: errmsg})
+	}
+	return true
+}
+
+// parseResult reads a single result after its opening brace. The result is
+// yielded in chunks if it has more rows than the limit.
+func (s *jsonStream) parseResult(yield func(jsonChunk) bool) bool {
+	var (
+		r       = &jsonResult{statementID: -1}
+		rows    int
+		partial bool
+	)
+	for s.dec.More() {
+		key, ok := s.key()
+		if !ok {
+			return false
+		}
+
+		switch key {
+		case "statement_id":
+			if !s.decode(&r.statementID) {
+				return false
+			}
+		case "series":
+			if !s.expect(json.Delim('[')) {
+				return false
+			}
+			for s.dec.More() {
+				if !s.expect(json.Delim('{')) || !s.parseSeries(&r, &rows, yield) {
+					return false
+				}
+
+				// Yield the series read so far if we have reached the
+				// limit and more series are coming.
+				if rows >= s.maxRows && s.dec.More() {
+					r.Partial = true
+					if !yield(jsonChunk{result: r}) {
+						return false
+					}
+					r, rows = &jsonResult{statementID: r.statementID}, 0
+				}
+			}
+			if !s.expect(json.Delim(']')) {
+				return false
+			}
+		case "messages":
+			var messages []*Message
+			if !s.decode(&messages) {
+				return false
+			}
+			r.MessageList = append(r.MessageList, messages...)
+		case "partial":
+			if !s.decode(&partial) {
+				return false
+			}
+		case "error":
+			if !s.decode(&r.Error) {
+				return false
+			}
+		default:
+			if !s.skip() {
+				return false
+			}
+		}
+	}
+
+	if !s.expect(json.Delim('}')) {
+		return false
+	}
+	r.Partial = partial
+	return yield(jsonChunk{result: r})
+}
+
+// parseSeries reads a single series after its opening brace and appends it
+// to the result. If the number of rows reaches the limit, the result is
+// yielded and replaced with a new result that continues the series.
+func (s *jsonStream) parseSeries(rp **jsonResult, rows *int, yield func(jsonChunk) bool) bool {
+	r := *rp
+	r.Series = append(r.Series, jsonSeriesData{})
+	v := &r.Series[len(r.Series)-1]
+
+	var partial bool
+	for s.dec.More() {
+		key, ok := s.key()
+		if !ok {
+			return false
+		}
+
+		switch key {
+		case "name":
+			if !s.decode(&v.Name) {
+				return false
+			}
+		case "tags":
+			if !s.decode(&v.Tags) {
+				return false
+			}
+		case "columns":
+			if !s.decode(&v.Columns) {
+				return false
+			}
+		case "values":
+			if !s.expect(json.Delim('[')) {
+				return false
+			}
+			for s.dec.More() {
+				if *rows >= s.maxRows {
+					v.Partial, r.Partial = true, true
+					if !yield(jsonChunk{result: r}) {
+						return false
+					}
+					r = &jsonResult{statementID: r.statementID, Series: []jsonSeriesData{{
+						Name:    v.Name,
+						Tags:    v.Tags,
+						Columns: v.Columns,
+					}}}
+					*rp, *rows = r, 0
+					v = &r.Series[0]
+				}
+
+				var row []interface{}
+				if !s.decode(&row) {
+					return false
+				}
+				v.Values = append(v.Values, row)
+				*rows++
+			}
+			if !s.expect(json.Delim(']')) {
+				return false
+			}
+		case "partial":
+			if !s.decode(&partial) {
+				return false
+			}
+		default:
+			if !s.skip() {
+				return false
+			}
+		}
+	}
+
+	if !s.expect(json.Delim('}')) {
+		return false
+	}
+	v.Partial = partial
+	return true
+}
+
+// key reads the next object key.
+func (s *jsonStream) key() (string, bool) {
+	tok, ok := s.token()
+	if !ok {
+		return "", false
+	}
+
+	key, ok := tok.(string)
+	if !ok {
+		s.err = fmt.Errorf("json: expected object key, got %v", tok)
+		return "", false
+	}
+	return key, true
+}
+
+// expect reads the next token and checks that it is the delimiter.
+func (s *jsonStream) expect(delim json.Delim) bool {
+	tok, ok := s.token()
+	if !ok {
+		return false
+	} else if tok != delim {
+		s.err = fmt.Errorf("json: expected %v, got %v", delim, tok)
+		return false
+	}
+	return true
+}
+
+// token reads the next token within a response.
+func (s *jsonStream) token() (json.Token, bool) {
+	tok, err := s.dec.Token()
+	if err != nil {
+		s.fail(err)
+		return nil, false
+	}
+	return tok, true
+}
+
+// decode decodes the next value within a response into v.
+func (s *jsonStream) decode(v interface{}) bool {
+	if err := s.dec.Decode(v); err != nil {
+		s.fail(err)
+		return false
+	}
+	return true
+}
+
+// skip discards the next value within a response.
+func (s *jsonStream) skip() bool {
+	var v json.RawMessage
+	return s.decode(&v)
+}
+
+// fail records an error that happened within a response. The stream is
+// truncated if it ends in the middle of a response.
+func (s *jsonStream) fail(err error) {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	s.err = err
+}
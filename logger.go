@@ -0,0 +1,39 @@
+package influxdb
+
+import "log/slog"
+
+// Logger is a structured logger used to report debug and warning events.
+// The arguments after the message are alternating keys and values in the
+// same style as log/slog. A *slog.Logger satisfies this interface.
+type Logger interface {
+	// Debug logs a message that is useful when diagnosing the client.
+	Debug(msg string, keysAndValues ...interface{})
+
+	// Warn logs a message about a problem that did not cause an error to be
+	// returned to the caller or that the caller may have missed.
+	Warn(msg string, keysAndValues ...interface{})
+}
+
+// NewSlogLogger returns a Logger that writes to the slog.Logger. If l is nil,
+// the default slog.Logger is used.
+func NewSlogLogger(l *slog.Logger) Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return l
+}
+
+// nopLogger discards all log messages.
+type nopLogger struct{}
+
+func (nopLogger) Debug(msg string, keysAndValues ...interface{}) {}
+func (nopLogger) Warn(msg string, keysAndValues ...interface{})  {}
+
+// loggerOrNop returns the logger or a logger that discards all messages if
+// the logger is nil.
+func loggerOrNop(l Logger) Logger {
+	if l == nil {
+		return nopLogger{}
+	}
+	return l
+}
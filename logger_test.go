@@ -0,0 +1,139 @@
+package influxdb_test
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb-client"
+)
+
+// logEntry is a single message recorded by testLogger.
+type logEntry struct {
+	level string
+	msg   string
+	attrs map[string]interface{}
+}
+
+// testLogger records every message that is logged.
+type testLogger struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (l *testLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.log("debug", msg, keysAndValues)
+}
+
+func (l *testLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.log("warn", msg, keysAndValues)
+}
+
+func (l *testLogger) log(level, msg string, keysAndValues []interface{}) {
+	attrs := make(map[string]interface{})
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		attrs[keysAndValues[i].(string)] = keysAndValues[i+1]
+	}
+
+	l.mu.Lock()
+	l.entries = append(l.entries, logEntry{level: level, msg: msg, attrs: attrs})
+	l.mu.Unlock()
+}
+
+func (l *testLogger) find(msg string) (logEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, e := range l.entries {
+		if e.msg == msg {
+			return e, true
+		}
+	}
+	return logEntry{}, false
+}
+
+func TestClient_Logger_Write(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "abc123")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"error":"partial write: field type conflict dropped=1"}`)
+	}))
+	defer server.Close()
+
+	client, err := influxdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := &testLogger{}
+	client.Logger = logger
+
+	writer := client.Writer()
+	writer.Database = "db0"
+	if _, err := writer.Write([]byte("cpu value=5\n")); err == nil {
+		t.Fatal("expected error")
+	}
+
+	e, ok := logger.find("partial write")
+	if !ok {
+		t.Fatalf("partial write was not logged: %#v", logger.entries)
+	}
+	if have, want := e.level, "warn"; have != want {
+		t.Errorf("level = %q; want %q", have, want)
+	}
+	if have, want := e.attrs["db"], "db0"; have != want {
+		t.Errorf("db = %#v; want %#v", have, want)
+	}
+	if have, want := e.attrs["request_id"], "abc123"; have != want {
+		t.Errorf("request_id = %#v; want %#v", have, want)
+	}
+	if _, ok := e.attrs["duration"].(time.Duration); !ok {
+		t.Errorf("duration = %#v; want a time.Duration", e.attrs["duration"])
+	}
+}
+
+func TestClient_Logger_SlowQuery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, `{"results":[{}]}`)
+	}))
+	defer server.Close()
+
+	client, err := influxdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := &testLogger{}
+	client.Logger = logger
+	client.SlowQueryThreshold = time.Millisecond
+
+	if err := client.Execute("SELECT value FROM cpu"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := logger.find("query"); !ok {
+		t.Errorf("query was not logged: %#v", logger.entries)
+	}
+	if e, ok := logger.find("slow query"); !ok {
+		t.Errorf("slow query was not logged: %#v", logger.entries)
+	} else if have, want := e.level, "warn"; have != want {
+		t.Errorf("level = %q; want %q", have, want)
+	}
+}
+
+func TestNewSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := influxdb.NewSlogLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	logger.Warn("flush failed", "db", "db0")
+
+	if have, want := buf.String(), "level=WARN msg=\"flush failed\" db=db0\n"; !strings.HasSuffix(have, want) {
+		t.Fatalf("unexpected output: have=%#v want suffix=%#v", have, want)
+	}
+}
@@ -0,0 +1,177 @@
+package influxdb
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultMonitorInterval = 10 * time.Second
+
+// MonitorOptions is a set of configuration options for configuring a
+// Monitor.
+type MonitorOptions struct {
+	// Database and RetentionPolicy are where the statistics are written.
+	Database        string
+	RetentionPolicy string
+
+	// Interval is how often the statistics are written. If this is zero, the
+	// statistics are written every 10 seconds.
+	Interval time.Duration
+
+	// Tags are added to every point written by the Monitor. This is usually
+	// used to identify the host or service the client is running in.
+	Tags Tags
+}
+
+// Monitor periodically writes the statistics of a Client back into InfluxDB
+// using the Client itself, in the same way the server records its own
+// statistics in the _internal database. It writes the following
+// measurements:
+//
+//	influxdb_client         requests, errors and latency for each operation
+//	                        and the points and bytes written
+//	influxdb_client_errors  failed responses with a status tag
+//	influxdb_client_writer  queue depth and flushes for each TimedWriter
+//	                        added with AddTimedWriter, with a writer tag
+type Monitor struct {
+	c   *Client
+	w   *HTTPWriter
+	opt MonitorOptions
+
+	mu      sync.Mutex
+	writers map[string]*TimedWriter
+
+	ticker *time.Ticker
+	done   chan struct{}
+	exited chan struct{}
+	once   sync.Once
+}
+
+// StartMonitor starts a Monitor that writes the statistics of this Client
+// using the options. The Monitor must be stopped using Stop or this will
+// leak a goroutine.
+func (c *Client) StartMonitor(opt MonitorOptions) *Monitor {
+	if opt.Interval <= 0 {
+		opt.Interval = defaultMonitorInterval
+	}
+
+	w := c.Writer()
+	w.Database = opt.Database
+	w.RetentionPolicy = opt.RetentionPolicy
+
+	m := &Monitor{
+		c:       c,
+		w:       w,
+		opt:     opt,
+		writers: make(map[string]*TimedWriter),
+		ticker:  time.NewTicker(opt.Interval),
+		done:    make(chan struct{}),
+		exited:  make(chan struct{}),
+	}
+	go m.loop()
+	return m
+}
+
+// AddTimedWriter includes the statistics of the TimedWriter in the points
+// written by the Monitor using the name as the writer tag.
+func (m *Monitor) AddTimedWriter(name string, w *TimedWriter) {
+	m.mu.Lock()
+	m.writers[name] = w
+	m.mu.Unlock()
+}
+
+// RemoveTimedWriter removes the TimedWriter with the name from the Monitor.
+func (m *Monitor) RemoveTimedWriter(name string) {
+	m.mu.Lock()
+	delete(m.writers, name)
+	m.mu.Unlock()
+}
+
+// Stop stops the Monitor. It is safe to call Stop multiple times.
+func (m *Monitor) Stop() {
+	m.once.Do(func() {
+		m.ticker.Stop()
+		close(m.done)
+	})
+	<-m.exited
+}
+
+// Points returns the points the Monitor would write for the current
+// statistics.
+func (m *Monitor) Points() []Point {
+	now := time.Now()
+	stats := m.c.Stats()
+
+	fields := map[string]interface{}{
+		"points_written": stats.PointsWritten,
+		"bytes_written":  stats.BytesWritten,
+		"partial_writes": stats.PartialWrites,
+	}
+	for prefix, op := range map[string]OperationStats{
+		"query": stats.Queries,
+		"write": stats.Writes,
+		"ping":  stats.Pings,
+	} {
+		fields[prefix+"_requests"] = op.Requests
+		fields[prefix+"_errors"] = op.Errors
+		fields[prefix+"_latency_mean_ns"] = int64(op.Latency.Mean())
+	}
+	points := []Point{{
+		Name:   "influxdb_client",
+		Tags:   m.opt.Tags,
+		Fields: fields,
+		Time:   now,
+	}}
+
+	for code, n := range stats.ErrorsByStatus {
+		points = append(points, Point{
+			Name:   "influxdb_client_errors",
+			Tags:   m.tags(Tag{Key: "status", Value: strconv.Itoa(code)}),
+			Fields: map[string]interface{}{"count": n},
+			Time:   now,
+		})
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for name, w := range m.writers {
+		ws := w.Stats()
+		points = append(points, Point{
+			Name: "influxdb_client_writer",
+			Tags: m.tags(Tag{Key: "writer", Value: name}),
+			Fields: map[string]interface{}{
+				"buffered_bytes": int64(w.Buffered()),
+				"flushes":        ws.Flushes,
+				"failed_flushes": ws.FailedFlushes,
+				"dropped_bytes":  ws.DroppedBytes,
+				"flush_time_ns":  int64(ws.FlushTime),
+			},
+			Time: now,
+		})
+	}
+	return points
+}
+
+// tags returns the configured tags with the additional tag.
+func (m *Monitor) tags(tag Tag) Tags {
+	tags := make(Tags, 0, len(m.opt.Tags)+1)
+	tags = append(tags, m.opt.Tags...)
+	return append(tags, tag)
+}
+
+// loop writes the statistics each interval until the Monitor is stopped.
+func (m *Monitor) loop() {
+	defer close(m.exited)
+	for {
+		select {
+		case <-m.ticker.C:
+			// Buffer the points so they are sent in as few requests as possible.
+			if _, err := WritePoints(NewBufferedWriter(m.w), m.Points()); err != nil {
+				m.c.logger().Warn("unable to write client statistics", "db", m.opt.Database, "error", err)
+			}
+		case <-m.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package influxdb_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb-client"
+)
+
+func TestMonitor(t *testing.T) {
+	bodies := make(chan string, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/write" && r.URL.Query().Get("db") == "_client" {
+			data, _ := ioutil.ReadAll(r.Body)
+			select {
+			case bodies <- string(data):
+			default:
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client, err := influxdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bw := influxdb.NewBufferedWriter(client.Writer())
+	tw := influxdb.NewTimedWriter(bw, time.Hour)
+	defer tw.Stop()
+	if _, err := tw.Write([]byte("cpu value=5\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	m := client.StartMonitor(influxdb.MonitorOptions{
+		Database: "_client",
+		Interval: 10 * time.Millisecond,
+		Tags:     influxdb.Tags{{Key: "host", Value: "server01"}},
+	})
+	defer m.Stop()
+	m.AddTimedWriter("cpu", tw)
+
+	var body string
+	select {
+	case body = <-bodies:
+	case <-time.After(time.Second):
+		t.Fatal("no statistics were written")
+	}
+
+	for _, prefix := range []string{
+		"influxdb_client,host=server01 ",
+		"influxdb_client_writer,host=server01,writer=cpu buffered_bytes=12i,",
+	} {
+		if !strings.Contains(body, "\n"+prefix) && !strings.HasPrefix(body, prefix) {
+			t.Errorf("missing %q in statistics:\n%s", prefix, body)
+		}
+	}
+
+	m.Stop()
+	m.Stop()
+}
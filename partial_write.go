@@ -0,0 +1,221 @@
+package influxdb
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	droppedRegex       = regexp.MustCompile(`\s*dropped=(\d+)\s*$`)
+	lineNumberRegex    = regexp.MustCompile(`\bline (\d+)\b`)
+	fieldConflictRegex = regexp.MustCompile(`input field "((?:[^"\\]|\\.)*)" on measurement "((?:[^"\\]|\\.)*)" is type (\w+)`)
+)
+
+// partialWriteReasons are the reasons the server gives for dropping points.
+var partialWriteReasons = []string{
+	"field type conflict",
+	"points beyond retention policy",
+	"unable to parse",
+	"max-values-per-tag limit exceeded",
+	"max series per database exceeded",
+}
+
+// parsePartialWrite parses the partial write message sent by the server in
+// response to writing the body.
+func parsePartialWrite(msg string, body []byte) ErrPartialWrite {
+	e := ErrPartialWrite{Err: msg}
+
+	reason := strings.TrimPrefix(msg, "partial write:")
+	if m := droppedRegex.FindStringSubmatchIndex(reason); m != nil {
+		e.Dropped, _ = strconv.Atoi(reason[m[2]:m[3]])
+		reason = reason[:m[0]]
+	}
+
+	var lines [][]byte
+	if len(body) > 0 {
+		lines = bytes.Split(bytes.TrimSuffix(body, []byte("\n")), []byte("\n"))
+	}
+
+	// Each reason is on its own line when the server reports multiple lines
+	// that could not be parsed.
+	var other string
+	unparsable := 0
+	for _, r := range strings.Split(strings.TrimSpace(reason), "\n") {
+		r = strings.TrimSpace(r)
+		switch {
+		case r == "":
+			continue
+		case strings.HasPrefix(r, "unable to parse"):
+			unparsable++
+			if i := unparsableLine(r, lines); i > 0 {
+				e.Lines = append(e.Lines, i)
+				continue
+			}
+		case other == "":
+			other = partialWriteReason(r)
+			if m := fieldConflictRegex.FindStringSubmatch(r); m != nil {
+				e.Lines = append(e.Lines, conflictingLines(lines, unescapeQuoted(m[2]), unescapeQuoted(m[1]), m[3])...)
+			}
+		}
+
+		for _, m := range lineNumberRegex.FindAllStringSubmatch(r, -1) {
+			if n, err := strconv.Atoi(m[1]); err == nil && !containsInt(e.Lines, n) {
+				e.Lines = append(e.Lines, n)
+			}
+		}
+	}
+
+	sort.Ints(e.Lines)
+
+	if unparsable > 0 || other != "" {
+		e.Reasons = make(map[string]int)
+	}
+	if unparsable > 0 {
+		e.Reasons["unable to parse"] = unparsable
+	}
+	if other != "" {
+		e.Reasons[other] = e.Dropped - unparsable
+	}
+	return e
+}
+
+// partialWriteReason returns the reason for the message. If the reason is
+// not known, the message up to the first colon is used.
+func partialWriteReason(msg string) string {
+	for _, reason := range partialWriteReasons {
+		if strings.HasPrefix(msg, reason) {
+			return reason
+		}
+	}
+	if i := strings.IndexByte(msg, ':'); i >= 0 {
+		return strings.TrimSpace(msg[:i])
+	}
+	return msg
+}
+
+// unparsableLine returns the line number of the line quoted in a parse error
+// or 0 if it cannot be found.
+func unparsableLine(msg string, lines [][]byte) int {
+	start := strings.IndexByte(msg, '\'')
+	end := strings.LastIndex(msg, "':")
+	if start < 0 || end <= start {
+		return 0
+	}
+
+	quoted := msg[start+1 : end]
+	for i, line := range lines {
+		if string(line) == quoted {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// conflictingLines returns the line numbers of the lines that write the field
+// to the measurement with the type. The server does not say which shard had
+// the conflict, so this includes lines that may have been accepted.
+func conflictingLines(lines [][]byte, measurement, field, typ string) []int {
+	var out []int
+	for i, line := range lines {
+		name, fields := scanLine(string(line))
+		if name != measurement {
+			continue
+		}
+		if t, ok := fields[field]; ok && t == typ {
+			out = append(out, i+1)
+		}
+	}
+	return out
+}
+
+// scanLine returns the measurement name and the type of each field in a
+// line of the line protocol.
+func scanLine(line string) (name string, fields map[string]string) {
+	key, i := scanUntil(line, 0, ' ', false)
+	name, _ = scanUntil(key, 0, ',', false)
+
+	section, _ := scanUntil(line, i+1, ' ', true)
+	fields = make(map[string]string)
+	for j := 0; j < len(section); {
+		field, next := scanUntil(section, j, ',', true)
+		k, v, _ := strings.Cut(field, "=")
+		fields[unescape(k)] = fieldType(v)
+		j = next + 1
+	}
+	return unescape(name), fields
+}
+
+// scanUntil returns the text from start until the first unescaped delimiter
+// and the index of the delimiter. If quoted is true, delimiters within
+// double quotes are ignored.
+func scanUntil(s string, start int, delim byte, quoted bool) (string, int) {
+	if start > len(s) {
+		return "", len(s)
+	}
+
+	inQuote := false
+	for i := start; i < len(s); i++ {
+		switch ch := s[i]; {
+		case ch == '\\':
+			i++
+		case quoted && ch == '"':
+			inQuote = !inQuote
+		case ch == delim && !inQuote:
+			return s[start:i], i
+		}
+	}
+	return s[start:], len(s)
+}
+
+// fieldType returns the type the server uses for a field value.
+func fieldType(v string) string {
+	switch {
+	case strings.HasPrefix(v, `"`):
+		return "string"
+	case strings.HasSuffix(v, "i"):
+		return "integer"
+	case strings.HasSuffix(v, "u"):
+		return "unsigned"
+	}
+	switch v {
+	case "t", "T", "true", "True", "TRUE", "f", "F", "false", "False", "FALSE":
+		return "boolean"
+	}
+	return "float"
+}
+
+// unescape removes the backslashes escaping characters in an identifier.
+func unescape(s string) string {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		buf.WriteByte(s[i])
+	}
+	return buf.String()
+}
+
+// unescapeQuoted removes the escaping from a string quoted by the server.
+func unescapeQuoted(s string) string {
+	if v, err := strconv.Unquote(`"` + s + `"`); err == nil {
+		return v
+	}
+	return s
+}
+
+func containsInt(a []int, v int) bool {
+	for _, x := range a {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -3,9 +3,23 @@ package influxdb
 import (
 	"bytes"
 	"io"
+	"math"
+	"sort"
+	"strings"
 	"time"
 )
 
+// MaxStringFieldLength is the maximum length of a string field value that
+// the server will accept.
+const MaxStringFieldLength = 64 * 1024
+
+var (
+	// minTime and maxTime are the earliest and latest times that can be
+	// represented by the server.
+	minTime = time.Unix(0, math.MinInt64+2).UTC()
+	maxTime = time.Unix(0, math.MaxInt64-1).UTC()
+)
+
 // Tag is a key/value pair of strings that is indexed when inserted into a measurement.
 type Tag struct {
 	Key   string
@@ -13,13 +27,46 @@ type Tag struct {
 }
 
 // Tags is a list of Tag structs. For optimal efficiency, this should be inserted
-// into InfluxDB in a sorted order and should only contain unique values.
+// into InfluxDB in a sorted order and should only contain unique values. The
+// line protocol sorts and deduplicates the tags when encoding a Point unless
+// sorting has been disabled with WithoutSorting.
 type Tags []Tag
 
 func (a Tags) Less(i, j int) bool { return a[i].Key < a[j].Key }
 func (a Tags) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a Tags) Len() int           { return len(a) }
 
+// normalize returns the tags sorted by key with duplicate keys removed. When
+// a key is present more than once, the last value wins. If the tags are
+// already sorted and unique, the original slice is returned so no allocation
+// is needed.
+func (a Tags) normalize() Tags {
+	unique := true
+	for i := 1; i < len(a); i++ {
+		if a[i-1].Key >= a[i].Key {
+			unique = false
+			break
+		}
+	}
+	if unique {
+		return a
+	}
+
+	tags := make(Tags, len(a))
+	copy(tags, a)
+	sort.Stable(tags)
+
+	n := 0
+	for i := range tags {
+		if i+1 < len(tags) && tags[i].Key == tags[i+1].Key {
+			continue
+		}
+		tags[n] = tags[i]
+		n++
+	}
+	return tags[:n]
+}
+
 func (a Tags) String() string {
 	var buf bytes.Buffer
 	for i, t := range a {
@@ -43,11 +90,77 @@ type Point struct {
 
 // WriteTo writes this Point to the io.Writer using the default write protocol.
 // If the target io.Writer is an Writer, this uses the Protocol associated with
-// that Writer.
+// that Writer unless that Writer does not have a Protocol.
 func (pt *Point) WriteTo(w io.Writer) (n int, err error) {
 	p := DefaultWriteProtocol
 	if w, ok := w.(Writer); ok {
-		p = w.Protocol()
+		if wp := w.Protocol(); wp != nil {
+			p = wp
+		}
 	}
 	return p.Encode(w, pt)
 }
+
+// Validate checks that the Point can be written to the server. This catches
+// errors that would otherwise only be reported by the server, such as empty
+// identifiers, newlines in identifiers, the reserved "time" key, string fields
+// that are too long, and times that cannot be represented. The returned error
+// is an ErrInvalidPoint.
+func (pt *Point) Validate() error {
+	invalid := func(key, reason string) error {
+		return ErrInvalidPoint{Name: pt.Name, Key: key, Reason: reason}
+	}
+
+	if pt.Name == "" {
+		return invalid("", "empty measurement name")
+	} else if hasNewline(pt.Name) {
+		return invalid("", "measurement name contains a newline")
+	}
+
+	for _, t := range pt.Tags {
+		switch {
+		case t.Key == "":
+			return invalid(t.Key, "empty tag key")
+		case t.Value == "":
+			return invalid(t.Key, "empty tag value")
+		case t.Key == "time":
+			return invalid(t.Key, "tag key is reserved")
+		case hasNewline(t.Key):
+			return invalid(t.Key, "tag key contains a newline")
+		case hasNewline(t.Value):
+			return invalid(t.Key, "tag value contains a newline")
+		}
+	}
+
+	if len(pt.Fields) == 0 {
+		return invalid("", ErrNoFields.Error())
+	}
+	for k, v := range pt.Fields {
+		switch {
+		case k == "":
+			return invalid(k, "empty field key")
+		case k == "time":
+			return invalid(k, "field key is reserved")
+		case hasNewline(k):
+			return invalid(k, "field key contains a newline")
+		}
+
+		if s, ok := v.(string); ok {
+			if len(s) > MaxStringFieldLength {
+				return invalid(k, "string field value is too long")
+			}
+		} else if _, err := appendValue(nil, v); err != nil {
+			return invalid(k, err.Error())
+		}
+	}
+
+	if !pt.Time.IsZero() && (pt.Time.Before(minTime) || pt.Time.After(maxTime)) {
+		return invalid("", "time is outside the range the server can store")
+	}
+	return nil
+}
+
+// hasNewline returns true if the string contains a newline.
+func hasNewline(s string) bool {
+	return strings.ContainsAny(s, "\r\n")
+}
@@ -2,7 +2,9 @@ package influxdb_test
 
 import (
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	influxdb "github.com/influxdata/influxdb-client"
 )
@@ -38,3 +40,70 @@ func TestTags_String(t *testing.T) {
 		t.Errorf("unexpected tags string: have=%#v want=%#v", have, want)
 	}
 }
+
+func TestPoint_Validate(t *testing.T) {
+	fields := map[string]interface{}{"value": 5.0}
+	tests := []struct {
+		pt   influxdb.Point
+		want error
+	}{
+		{
+			pt: influxdb.Point{
+				Name:   "cpu",
+				Tags:   influxdb.Tags{{Key: "host", Value: "server01"}},
+				Fields: fields,
+				Time:   time.Unix(10, 0),
+			},
+		},
+		{
+			pt:   influxdb.Point{Fields: fields},
+			want: influxdb.ErrInvalidPoint{Reason: "empty measurement name"},
+		},
+		{
+			pt:   influxdb.Point{Name: "cpu\nmem", Fields: fields},
+			want: influxdb.ErrInvalidPoint{Name: "cpu\nmem", Reason: "measurement name contains a newline"},
+		},
+		{
+			pt:   influxdb.Point{Name: "cpu", Tags: influxdb.Tags{{Value: "server01"}}, Fields: fields},
+			want: influxdb.ErrInvalidPoint{Name: "cpu", Reason: "empty tag key"},
+		},
+		{
+			pt:   influxdb.Point{Name: "cpu", Tags: influxdb.Tags{{Key: "host"}}, Fields: fields},
+			want: influxdb.ErrInvalidPoint{Name: "cpu", Key: "host", Reason: "empty tag value"},
+		},
+		{
+			pt:   influxdb.Point{Name: "cpu", Tags: influxdb.Tags{{Key: "time", Value: "now"}}, Fields: fields},
+			want: influxdb.ErrInvalidPoint{Name: "cpu", Key: "time", Reason: "tag key is reserved"},
+		},
+		{
+			pt:   influxdb.Point{Name: "cpu", Tags: influxdb.Tags{{Key: "host", Value: "server\r01"}}, Fields: fields},
+			want: influxdb.ErrInvalidPoint{Name: "cpu", Key: "host", Reason: "tag value contains a newline"},
+		},
+		{
+			pt:   influxdb.Point{Name: "cpu"},
+			want: influxdb.ErrInvalidPoint{Name: "cpu", Reason: "no fields"},
+		},
+		{
+			pt:   influxdb.Point{Name: "cpu", Fields: map[string]interface{}{"time": 5.0}},
+			want: influxdb.ErrInvalidPoint{Name: "cpu", Key: "time", Reason: "field key is reserved"},
+		},
+		{
+			pt:   influxdb.Point{Name: "cpu", Fields: map[string]interface{}{"value": strings.Repeat("a", influxdb.MaxStringFieldLength+1)}},
+			want: influxdb.ErrInvalidPoint{Name: "cpu", Key: "value", Reason: "string field value is too long"},
+		},
+		{
+			pt:   influxdb.Point{Name: "cpu", Fields: map[string]interface{}{"value": struct{}{}}},
+			want: influxdb.ErrInvalidPoint{Name: "cpu", Key: "value", Reason: "invalid field type: struct {}"},
+		},
+		{
+			pt:   influxdb.Point{Name: "cpu", Fields: fields, Time: time.Date(2300, 1, 1, 0, 0, 0, 0, time.UTC)},
+			want: influxdb.ErrInvalidPoint{Name: "cpu", Reason: "time is outside the range the server can store"},
+		},
+	}
+
+	for i, tt := range tests {
+		if have := tt.pt.Validate(); have != tt.want {
+			t.Errorf("%d. Validate() = %v; want %v", i, have, tt.want)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package influxdb
 
-import "fmt"
+import (
+	"io"
+	"time"
+)
 
 // Precision is the requested precision.
 type Precision string
@@ -29,33 +32,119 @@ func (p Precision) String() string {
 	return string(p)
 }
 
+// Duration returns the length of one unit of this precision. An empty or
+// unknown precision is treated as nanosecond precision.
+func (p Precision) Duration() time.Duration {
+	switch p {
+	case PrecisionHour:
+		return time.Hour
+	case PrecisionMinute:
+		return time.Minute
+	case PrecisionSecond:
+		return time.Second
+	case PrecisionMillisecond:
+		return time.Millisecond
+	case PrecisionMicrosecond:
+		return time.Microsecond
+	default:
+		return time.Nanosecond
+	}
+}
+
+// PrecisionProtocol is an optional interface that can be implemented by a
+// Protocol that is able to encode timestamps with a precision natively.
+type PrecisionProtocol interface {
+	Protocol
+
+	// Precision returns the precision timestamps are encoded with.
+	Precision() Precision
+
+	// WithPrecision returns a copy of the protocol that will encode
+	// timestamps with the given precision.
+	WithPrecision(precision Precision) Protocol
+}
+
 // WithPrecision augments the protocol with the given precision. If the
 // protocol cannot be augmented natively, this wraps it in a protocol that will
 // truncate the time for any encoded points to the given precision.
 func WithPrecision(protocol Protocol, precision Precision) Protocol {
-	// Switch on known protocols to avoid reflection.
 	switch protocol := protocol.(type) {
-	case *lineProtocolV1:
-		newP := &lineProtocolV1{}
-		if protocol != nil {
-			*newP = *protocol
+	case nil:
+		return WithPrecision(DefaultWriteProtocol, precision)
+	case PrecisionProtocol:
+		return protocol.WithPrecision(precision)
+	case *precisionProtocol:
+		// Replace the existing wrapper instead of wrapping it again.
+		if protocol.round {
+			return WithRoundedPrecision(protocol.Protocol, precision)
 		}
-		newP.Precision = precision
-		return newP
+		return WithPrecision(protocol.Protocol, precision)
+	case *validatingProtocol:
+		return WithValidation(WithPrecision(protocol.Protocol, precision))
 	default:
-		// TODO(jsternberg): Implement the augmented protocol.
-		panic(fmt.Sprintf("unsupported protocol type: %T", protocol))
+		return &precisionProtocol{Protocol: protocol, precision: precision}
+	}
+}
+
+// WithRoundedPrecision is the same as WithPrecision, but the time for any
+// encoded points is rounded to the nearest multiple of the precision instead
+// of being truncated.
+func WithRoundedPrecision(protocol Protocol, precision Precision) Protocol {
+	if p, ok := protocol.(*precisionProtocol); ok {
+		protocol = p.Protocol
+	}
+	return &precisionProtocol{
+		Protocol:  WithPrecision(protocol, precision),
+		precision: precision,
+		round:     true,
 	}
 }
 
-// GetPrecision retrieves the precision from a protocol. If the precision could
-// not be discovered, this returns an empty string.
+// GetPrecision retrieves the precision from a protocol. If the protocol does
+// not report its precision, it is assumed to encode timestamps with
+// nanosecond precision.
 func GetPrecision(protocol Protocol) Precision {
 	switch protocol := protocol.(type) {
-	case *lineProtocolV1:
-		if protocol != nil {
-			return protocol.Precision
-		}
+	case PrecisionProtocol:
+		return protocol.Precision()
+	case *precisionProtocol:
+		// The wrapper only modifies the time of the point. The inner protocol
+		// still decides the precision that timestamps are encoded with.
+		return GetPrecision(protocol.Protocol)
+	case *validatingProtocol:
+		return GetPrecision(protocol.Protocol)
 	}
 	return PrecisionNanosecond
 }
+
+// precisionProtocol wraps a Protocol and modifies the time of each point to
+// the precision before the point is encoded by the inner Protocol.
+type precisionProtocol struct {
+	Protocol
+	precision Precision
+	round     bool
+}
+
+func (p *precisionProtocol) Encode(w io.Writer, pt *Point) (n int, err error) {
+	return p.Protocol.Encode(w, p.convert(pt))
+}
+
+func (p *precisionProtocol) AppendPoint(dst []byte, pt *Point) ([]byte, error) {
+	return appendPoint(p.Protocol, dst, p.convert(pt))
+}
+
+// convert returns a Point with the time modified to the precision. The
+// original Point is returned if it has no time.
+func (p *precisionProtocol) convert(pt *Point) *Point {
+	if pt.Time.IsZero() {
+		return pt
+	}
+
+	other := *pt
+	if d := p.precision.Duration(); p.round {
+		other.Time = pt.Time.Round(d)
+	} else {
+		other.Time = pt.Time.Truncate(d)
+	}
+	return &other
+}
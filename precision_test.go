@@ -1,7 +1,10 @@
 package influxdb_test
 
 import (
+	"bytes"
+	"io"
 	"testing"
+	"time"
 
 	influxdb "github.com/influxdata/influxdb-client"
 )
@@ -43,3 +46,80 @@ func TestPrecision_String(t *testing.T) {
 		}
 	}
 }
+
+// customProtocol is a Protocol that does not support precision natively.
+type customProtocol struct{}
+
+func (customProtocol) Encode(w io.Writer, pt *influxdb.Point) (n int, err error) {
+	return influxdb.Encode(w, pt)
+}
+
+func (customProtocol) ContentType() string {
+	return "text/plain"
+}
+
+func TestWithPrecision_Custom(t *testing.T) {
+	var buf bytes.Buffer
+	pt := influxdb.Point{
+		Name:   "cpu",
+		Fields: map[string]interface{}{"value": float64(5)},
+		Time:   time.Unix(7265, 769142873),
+	}
+
+	p := influxdb.WithPrecision(customProtocol{}, influxdb.PrecisionSecond)
+	if _, err := p.Encode(&buf, &pt); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if have, want := buf.String(), "cpu value=5 7265000000000\n"; have != want {
+		t.Errorf("unexpected output: have=%#v want=%#v", have, want)
+	}
+	buf.Reset()
+
+	// The timestamps are still encoded in nanoseconds by the inner protocol.
+	if have, want := influxdb.GetPrecision(p), influxdb.PrecisionNanosecond; have != want {
+		t.Errorf("GetPrecision() = %q; want %q", have, want)
+	}
+	if have, want := p.ContentType(), "text/plain"; have != want {
+		t.Errorf("ContentType() = %q; want %q", have, want)
+	}
+
+	p = influxdb.WithRoundedPrecision(p, influxdb.PrecisionMillisecond)
+	if _, err := p.Encode(&buf, &pt); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if have, want := buf.String(), "cpu value=5 7265769000000\n"; have != want {
+		t.Errorf("unexpected output: have=%#v want=%#v", have, want)
+	}
+	buf.Reset()
+
+	p = influxdb.WithPrecision(p, influxdb.PrecisionMicrosecond)
+	if _, err := p.Encode(&buf, &pt); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if have, want := buf.String(), "cpu value=5 7265769143000\n"; have != want {
+		t.Errorf("unexpected output: have=%#v want=%#v", have, want)
+	}
+}
+
+func TestWithRoundedPrecision_LineProtocol(t *testing.T) {
+	var buf bytes.Buffer
+	pt := influxdb.Point{
+		Name:   "cpu",
+		Fields: map[string]interface{}{"value": float64(5)},
+		Time:   time.Unix(7265, 769142873),
+	}
+
+	p := influxdb.WithRoundedPrecision(influxdb.DefaultWriteProtocol, influxdb.PrecisionSecond)
+	if _, err := p.Encode(&buf, &pt); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if have, want := buf.String(), "cpu value=5 7266\n"; have != want {
+		t.Errorf("unexpected output: have=%#v want=%#v", have, want)
+	}
+
+	if have, want := influxdb.GetPrecision(p), influxdb.PrecisionSecond; have != want {
+		t.Errorf("GetPrecision() = %q; want %q", have, want)
+	}
+}
+
+func TestGetPrecision_Custom(t *testing.T) {
+	if have, want := influxdb.GetPrecision(customProtocol{}), influxdb.PrecisionNanosecond; have != want {
+		t.Errorf("GetPrecision() = %q; want %q", have, want)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"math"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -40,150 +43,379 @@ func Encode(w io.Writer, pt *Point) (n int, err error) {
 	return DefaultWriteProtocol.Encode(w, pt)
 }
 
+// Appender is an optional interface that can be implemented by a Protocol
+// that is able to append an encoded Point directly to a byte slice.
+type Appender interface {
+	// AppendPoint appends the encoded Point to dst and returns the extended
+	// slice. If an error is returned, dst is returned unmodified.
+	AppendPoint(dst []byte, pt *Point) ([]byte, error)
+}
+
+// AppendPoint appends the point encoded with the DefaultWriteProtocol to dst
+// and returns the extended slice. If an error is returned, dst is returned
+// unmodified. Reusing dst between calls avoids allocating a new buffer for
+// each point.
+func AppendPoint(dst []byte, pt *Point) ([]byte, error) {
+	return appendPoint(DefaultWriteProtocol, dst, pt)
+}
+
+// appendPoint appends the point encoded with the protocol to dst. If the
+// protocol is not an Appender, the point is encoded into a buffer wrapping
+// dst instead.
+func appendPoint(p Protocol, dst []byte, pt *Point) ([]byte, error) {
+	if p, ok := p.(Appender); ok {
+		return p.AppendPoint(dst, pt)
+	}
+	buf := bytes.NewBuffer(dst)
+	if _, err := p.Encode(buf, pt); err != nil {
+		return dst, err
+	}
+	return buf.Bytes(), nil
+}
+
 var _bufpool = &sync.Pool{
 	New: func() interface{} {
-		return bytes.NewBuffer(make([]byte, 0, 64))
+		buf := make([]byte, 0, 64)
+		return &buf
 	},
 }
 
+// fieldKeys is a sortable list of field keys. The methods use a pointer
+// receiver so sorting a pooled list does not allocate.
+type fieldKeys []string
+
+func (a *fieldKeys) Len() int           { return len(*a) }
+func (a *fieldKeys) Less(i, j int) bool { return (*a)[i] < (*a)[j] }
+func (a *fieldKeys) Swap(i, j int)      { (*a)[i], (*a)[j] = (*a)[j], (*a)[i] }
+
+var _keypool = &sync.Pool{
+	New: func() interface{} {
+		return new(fieldKeys)
+	},
+}
+
+var (
+	_ PrecisionProtocol = &lineProtocolV1{}
+	_ Appender          = &lineProtocolV1{}
+)
+
 type lineProtocolV1 struct {
-	Precision Precision
+	precision Precision
+	unsorted  bool
+	strict    bool
 }
 
-func (p *lineProtocolV1) Encode(w io.Writer, pt *Point) (n int, err error) {
-	if len(pt.Fields) == 0 {
-		return 0, ErrNoFields
+func (p *lineProtocolV1) Precision() Precision {
+	if p == nil || p.precision == "" {
+		return PrecisionNanosecond
 	}
+	return p.precision
+}
 
-	precisionFactor := int64(1)
+func (p *lineProtocolV1) WithPrecision(precision Precision) Protocol {
+	newP := &lineProtocolV1{}
 	if p != nil {
-		switch p.Precision {
-		case PrecisionHour:
-			precisionFactor = int64(time.Hour)
-		case PrecisionMinute:
-			precisionFactor = int64(time.Minute)
-		case PrecisionSecond:
-			precisionFactor = int64(time.Second)
-		case PrecisionMillisecond:
-			precisionFactor = int64(time.Millisecond)
-		case PrecisionMicrosecond:
-			precisionFactor = int64(time.Microsecond)
-		case PrecisionNanosecond:
-			// no change in the precision factor is needed
-		}
+		*newP = *p
+	}
+	newP.precision = precision
+	return newP
+}
+
+func (p *lineProtocolV1) Encode(w io.Writer, pt *Point) (n int, err error) {
+	bp := _bufpool.Get().(*[]byte)
+	buf, err := p.AppendPoint((*bp)[:0], pt)
+	if err == nil {
+		n, err = w.Write(buf)
 	}
+	*bp = buf[:0]
+	_bufpool.Put(bp)
+	return n, err
+}
 
-	buf := _bufpool.Get().(*bytes.Buffer)
-	buf.WriteString(escapeMeasurement(pt.Name))
-	if len(pt.Tags) > 0 {
-		for _, t := range pt.Tags {
-			buf.WriteString(",")
-			buf.WriteString(escapeTag(t.Key))
-			buf.WriteString("=")
-			buf.WriteString(escapeTag(t.Value))
+func (p *lineProtocolV1) AppendPoint(dst []byte, pt *Point) ([]byte, error) {
+	if len(pt.Fields) == 0 {
+		return dst, ErrNoFields
+	} else if p != nil && p.strict {
+		if err := pt.Validate(); err != nil {
+			return dst, err
 		}
 	}
-	buf.WriteString(" ")
 
-	i := 0
-	for k, v := range pt.Fields {
-		if i > 0 {
-			buf.WriteString(",")
+	buf := p.appendSeriesKey(dst, pt.Name, pt.Tags)
+	buf = append(buf, ' ')
+	buf, err := p.appendFields(buf, pt.Fields, pt.Time)
+	if err != nil {
+		return dst, err
+	}
+	return buf, nil
+}
+
+// appendSeriesKey appends the escaped measurement name and tags to dst.
+func (p *lineProtocolV1) appendSeriesKey(dst []byte, name string, tags Tags) []byte {
+	if p == nil || !p.unsorted {
+		tags = tags.normalize()
+	}
+
+	buf := appendEscaped(dst, name, measurementEscapeChars)
+	for _, t := range tags {
+		buf = append(buf, ',')
+		buf = appendEscaped(buf, t.Key, tagEscapeChars)
+		buf = append(buf, '=')
+		buf = appendEscaped(buf, t.Value, tagEscapeChars)
+	}
+	return buf
+}
+
+// appendFields appends the fields, the time and the terminating newline to
+// dst. If an error is returned, dst is returned unmodified.
+func (p *lineProtocolV1) appendFields(dst []byte, fields map[string]interface{}, t time.Time) ([]byte, error) {
+	if len(fields) == 0 {
+		return dst, ErrNoFields
+	}
+
+	buf := dst
+	var err error
+	if len(fields) == 1 || (p != nil && p.unsorted) {
+		i := 0
+		for k, v := range fields {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			if buf, err = appendField(buf, k, v); err != nil {
+				return dst, err
+			}
+			i++
+		}
+	} else {
+		keys := _keypool.Get().(*fieldKeys)
+		for k := range fields {
+			*keys = append(*keys, k)
+		}
+		sort.Sort(keys)
+
+		for i, k := range *keys {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			if buf, err = appendField(buf, k, fields[k]); err != nil {
+				break
+			}
 		}
-		buf.WriteString(escapeString(k))
-		buf.WriteString("=")
 
-		value, err := formatValue(v)
+		// Clear the keys so the pool does not hold onto the strings.
+		for i := range *keys {
+			(*keys)[i] = ""
+		}
+		*keys = (*keys)[:0]
+		_keypool.Put(keys)
+
 		if err != nil {
-			return 0, err
+			return dst, err
 		}
-		buf.WriteString(value)
-		i++
-	}
-	if !pt.Time.IsZero() {
-		buf.WriteString(" ")
-		ts := pt.Time.UnixNano() / precisionFactor
-		buf.WriteString(strconv.FormatInt(ts, 10))
 	}
-	buf.WriteString("\n")
 
-	n, err = w.Write(buf.Bytes())
-	buf.Reset()
-	_bufpool.Put(buf)
-	return
+	if !t.IsZero() {
+		buf = append(buf, ' ')
+		buf = strconv.AppendInt(buf, t.UnixNano()/int64(p.Precision().Duration()), 10)
+	}
+	return append(buf, '\n'), nil
 }
 
 func (*lineProtocolV1) ContentType() string {
 	return "application/x-influxdb-line-protocol-v1"
 }
 
-type escapeSequence struct {
-	s   string
-	esc string
-}
-
-var (
-	measurementEscapeCodes = []escapeSequence{
-		{s: `,`, esc: `\,`},
-		{s: ` `, esc: `\ `},
+// appendField appends a single field key and value to the buffer.
+func appendField(buf []byte, k string, v interface{}) ([]byte, error) {
+	n := len(buf)
+	buf = appendEscaped(buf, k, tagEscapeChars)
+	buf = append(buf, '=')
+	buf, err := appendValue(buf, v)
+	if err != nil {
+		return buf[:n], err
 	}
+	return buf, nil
+}
 
-	tagEscapeCodes = []escapeSequence{
-		{s: `,`, esc: `\,`},
-		{s: ` `, esc: `\ `},
-		{s: `=`, esc: `\=`},
+// WithoutSorting returns a copy of the protocol that writes fields in map
+// iteration order and writes tags exactly as they are given. By default, the
+// line protocol sorts fields by key and sorts and deduplicates tags so the
+// same Point always encodes to the same bytes. Disabling this is faster, but
+// the output is no longer deterministic and tags must already be sorted and
+// unique for optimal performance on the server.
+//
+// Protocols that do not sort their output are returned unchanged.
+func WithoutSorting(protocol Protocol) Protocol {
+	switch protocol := protocol.(type) {
+	case *lineProtocolV1:
+		newP := &lineProtocolV1{}
+		if protocol != nil {
+			*newP = *protocol
+		}
+		newP.unsorted = true
+		return newP
+	case *precisionProtocol:
+		other := *protocol
+		other.Protocol = WithoutSorting(protocol.Protocol)
+		return &other
+	case *validatingProtocol:
+		return WithValidation(WithoutSorting(protocol.Protocol))
+	default:
+		return protocol
 	}
+}
 
-	stringEscapeCodes = []escapeSequence{
-		{s: `\`, esc: `\\`},
-		{s: `"`, esc: `\"`},
+// WithValidation returns a copy of the protocol that validates each Point
+// with Point.Validate before encoding it. Invalid points return an
+// ErrInvalidPoint and nothing is written.
+func WithValidation(protocol Protocol) Protocol {
+	switch protocol := protocol.(type) {
+	case *lineProtocolV1:
+		newP := &lineProtocolV1{}
+		if protocol != nil {
+			*newP = *protocol
+		}
+		newP.strict = true
+		return newP
+	case *precisionProtocol:
+		other := *protocol
+		other.Protocol = WithValidation(protocol.Protocol)
+		return &other
+	case *validatingProtocol:
+		return protocol
+	default:
+		return &validatingProtocol{Protocol: protocol}
 	}
-)
+}
 
-// escapeMeasurement escapes a measurement.
-func escapeMeasurement(in string) string {
-	return escape(in, measurementEscapeCodes)
+// validatingProtocol wraps a Protocol and validates each Point before it is
+// encoded by the inner Protocol.
+type validatingProtocol struct {
+	Protocol
 }
 
-// escapeTag escapes a tag key or value.
-func escapeTag(in string) string {
-	return escape(in, tagEscapeCodes)
+func (p *validatingProtocol) Encode(w io.Writer, pt *Point) (n int, err error) {
+	if err := pt.Validate(); err != nil {
+		return 0, err
+	}
+	return p.Protocol.Encode(w, pt)
 }
 
-// escapeString escapes a string field key or value.
-func escapeString(in string) string {
-	return escape(in, stringEscapeCodes)
+func (p *validatingProtocol) AppendPoint(dst []byte, pt *Point) ([]byte, error) {
+	if err := pt.Validate(); err != nil {
+		return dst, err
+	}
+	return appendPoint(p.Protocol, dst, pt)
 }
 
-// escape the string with the given escape sequences.
-func escape(in string, codes []escapeSequence) string {
-	for _, c := range codes {
-		in = strings.Replace(in, c.s, c.esc, -1)
+const (
+	// measurementEscapeChars are the characters escaped in a measurement.
+	measurementEscapeChars = ", "
+
+	// tagEscapeChars are the characters escaped in a tag key, tag value or
+	// field key.
+	tagEscapeChars = ",= "
+
+	// stringEscapeChars are the characters escaped in a string field value.
+	stringEscapeChars = `\"`
+)
+
+// appendEscaped appends the string to dst with a backslash inserted before
+// each of the characters in chars. The string is only scanned once and, when
+// nothing needs to be escaped, it is appended as is.
+func appendEscaped(dst []byte, s string, chars string) []byte {
+	for {
+		i := strings.IndexAny(s, chars)
+		if i < 0 {
+			return append(dst, s...)
+		}
+		dst = append(dst, s[:i]...)
+		dst = append(dst, '\\', s[i])
+		s = s[i+1:]
 	}
-	return in
 }
 
-// formatValue formats a value as a string.
-func formatValue(v interface{}) (string, error) {
+// appendValue appends the formatted value to dst.
+//
+// Floats are formatted with the shortest representation that will parse back
+// to the same value. Signed integers are suffixed with "i" and unsigned
+// integers are suffixed with "u". Named types are formatted using their
+// underlying kind.
+func appendValue(dst []byte, v interface{}) ([]byte, error) {
 	switch v := v.(type) {
 	case float64:
-		return strconv.FormatFloat(v, 'g', 6, 64), nil
+		return appendFloat(dst, v, 64)
 	case float32:
-		return strconv.FormatFloat(float64(v), 'g', 6, 64), nil
+		return appendFloat(dst, float64(v), 32)
 	case int64:
-		return strconv.FormatInt(v, 10) + "i", nil
+		return append(strconv.AppendInt(dst, v, 10), 'i'), nil
 	case int32:
-		return strconv.FormatInt(int64(v), 10) + "i", nil
+		return append(strconv.AppendInt(dst, int64(v), 10), 'i'), nil
 	case int:
-		return strconv.Itoa(v) + "i", nil
+		return append(strconv.AppendInt(dst, int64(v), 10), 'i'), nil
+	case uint64:
+		return append(strconv.AppendUint(dst, v, 10), 'u'), nil
+	case uint:
+		return append(strconv.AppendUint(dst, uint64(v), 10), 'u'), nil
 	case string:
-		return `"` + escapeString(v) + `"`, nil
+		return appendString(dst, v), nil
 	case bool:
-		if v {
-			return "t", nil
-		}
-		return "f", nil
+		return appendBool(dst, v), nil
+	case nil:
+		return dst, fmt.Errorf("invalid field type: %T", v)
+	}
+
+	// Fall back to reflection for the less common integer widths and for
+	// named types.
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float64:
+		return appendFloat(dst, rv.Float(), 64)
+	case reflect.Float32:
+		return appendFloat(dst, rv.Float(), 32)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return append(strconv.AppendInt(dst, rv.Int(), 10), 'i'), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return append(strconv.AppendUint(dst, rv.Uint(), 10), 'u'), nil
+	case reflect.String:
+		return appendString(dst, rv.String()), nil
+	case reflect.Bool:
+		return appendBool(dst, rv.Bool()), nil
 	default:
-		return "", fmt.Errorf("invalid field type: %T", v)
+		return dst, fmt.Errorf("invalid field type: %T", v)
+	}
+}
+
+// appendString appends the quoted and escaped string field value to dst.
+func appendString(dst []byte, v string) []byte {
+	dst = append(dst, '"')
+	dst = appendEscaped(dst, v, stringEscapeChars)
+	return append(dst, '"')
+}
+
+// appendBool appends the boolean field value to dst.
+func appendBool(dst []byte, v bool) []byte {
+	if v {
+		return append(dst, 't')
+	}
+	return append(dst, 'f')
+}
+
+// appendFloat appends a float with the shortest representation that will
+// parse back to the same value. Like encoding/json, exponent notation is only
+// used for very large or very small values. NaN and infinite values cannot be
+// represented in the line protocol and return ErrInvalidFloat.
+func appendFloat(dst []byte, v float64, bitSize int) ([]byte, error) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return dst, ErrInvalidFloat
+	}
+
+	format := byte('f')
+	if abs := math.Abs(v); abs != 0 {
+		if bitSize == 64 && (abs < 1e-6 || abs >= 1e21) ||
+			bitSize == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
 	}
+	return strconv.AppendFloat(dst, v, format, -1, bitSize), nil
 }
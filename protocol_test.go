@@ -3,6 +3,7 @@ package influxdb_test
 import (
 	"bytes"
 	"io/ioutil"
+	"math"
 	"testing"
 	"time"
 
@@ -77,7 +78,7 @@ func TestLineProtocol_V1_ErrNoFields(t *testing.T) {
 
 	pt := influxdb.Point{Name: "cpu"}
 	if _, err := p.Encode(&buf, &pt); err != influxdb.ErrNoFields {
-		t.Fatal("unexpected error: %v", err)
+		t.Fatalf("unexpected error: %v", err)
 	}
 }
 
@@ -227,6 +228,233 @@ func TestLineProtocol_V1_Fields(t *testing.T) {
 	buf.Reset()
 }
 
+func TestLineProtocol_V1_FieldValues(t *testing.T) {
+	type myInt int16
+	type myString string
+
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{value: float64(0.1), want: "cpu value=0.1\n"},
+		{value: float64(1234567.891), want: "cpu value=1234567.891\n"},
+		{value: float64(1e21), want: "cpu value=1e+21\n"},
+		{value: float64(1e-7), want: "cpu value=1e-07\n"},
+		{value: float64(3.14159265358979), want: "cpu value=3.14159265358979\n"},
+		{value: float32(0.1), want: "cpu value=0.1\n"},
+		{value: int8(-5), want: "cpu value=-5i\n"},
+		{value: int16(5), want: "cpu value=5i\n"},
+		{value: uint8(5), want: "cpu value=5u\n"},
+		{value: uint16(5), want: "cpu value=5u\n"},
+		{value: uint32(5), want: "cpu value=5u\n"},
+		{value: uint(5), want: "cpu value=5u\n"},
+		{value: uint64(18446744073709551615), want: "cpu value=18446744073709551615u\n"},
+		{value: myInt(5), want: "cpu value=5i\n"},
+		{value: myString("foo"), want: "cpu value=\"foo\"\n"},
+	}
+
+	p := influxdb.LineProtocol.V1()
+	for i, tt := range tests {
+		var buf bytes.Buffer
+		pt := influxdb.Point{
+			Name:   "cpu",
+			Fields: map[string]interface{}{"value": tt.value},
+		}
+		if _, err := p.Encode(&buf, &pt); err != nil {
+			t.Errorf("%d. unexpected error: %s", i, err)
+		} else if have := buf.String(); have != tt.want {
+			t.Errorf("%d. unexpected output: have=%#v want=%#v", i, have, tt.want)
+		}
+	}
+}
+
+func TestLineProtocol_V1_InvalidFloat(t *testing.T) {
+	p := influxdb.LineProtocol.V1()
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		var buf bytes.Buffer
+		pt := influxdb.Point{
+			Name:   "cpu",
+			Fields: map[string]interface{}{"value": v},
+		}
+		if _, err := p.Encode(&buf, &pt); err != influxdb.ErrInvalidFloat {
+			t.Errorf("unexpected error for %v: have=%v want=%v", v, err, influxdb.ErrInvalidFloat)
+		}
+	}
+}
+
+func TestLineProtocol_V1_Sorted(t *testing.T) {
+	var buf bytes.Buffer
+	p := influxdb.LineProtocol.V1()
+
+	pt := influxdb.Point{
+		Name: "cpu",
+		Tags: []influxdb.Tag{
+			{Key: "region", Value: "uswest"},
+			{Key: "host", Value: "server01"},
+			{Key: "region", Value: "useast"},
+		},
+		Fields: map[string]interface{}{
+			"value": float64(5),
+			"idle":  float64(95),
+			"user":  int64(3),
+			"alive": true,
+		},
+	}
+
+	// Encode multiple times to catch any nondeterminism from map iteration.
+	for i := 0; i < 10; i++ {
+		if _, err := p.Encode(&buf, &pt); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if have, want := buf.String(), "cpu,host=server01,region=useast alive=t,idle=95,user=3i,value=5\n"; have != want {
+			t.Fatalf("unexpected output: have=%#v want=%#v", have, want)
+		}
+		buf.Reset()
+	}
+
+	// The original tags should not have been modified.
+	if have, want := pt.Tags[0].Key, "region"; have != want {
+		t.Errorf("unexpected tag key: have=%#v want=%#v", have, want)
+	}
+}
+
+func TestLineProtocol_V1_WithoutSorting(t *testing.T) {
+	var buf bytes.Buffer
+	p := influxdb.WithPrecision(influxdb.WithoutSorting(influxdb.LineProtocol.V1()), influxdb.PrecisionSecond)
+
+	pt := influxdb.Point{
+		Name: "cpu",
+		Tags: []influxdb.Tag{
+			{Key: "region", Value: "useast"},
+			{Key: "host", Value: "server01"},
+		},
+		Fields: map[string]interface{}{
+			"value": float64(5),
+		},
+		Time: time.Unix(10, 0),
+	}
+
+	if _, err := p.Encode(&buf, &pt); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if have, want := buf.String(), "cpu,region=useast,host=server01 value=5 10\n"; have != want {
+		t.Errorf("unexpected output: have=%#v want=%#v", have, want)
+	}
+}
+
+func TestLineProtocol_V1_WithValidation(t *testing.T) {
+	var buf bytes.Buffer
+	p := influxdb.WithValidation(influxdb.LineProtocol.V1())
+
+	pt := influxdb.Point{
+		Name:   "cpu",
+		Tags:   []influxdb.Tag{{Key: "host", Value: ""}},
+		Fields: map[string]interface{}{"value": float64(5)},
+	}
+
+	want := influxdb.ErrInvalidPoint{Name: "cpu", Key: "host", Reason: "empty tag value"}
+	if _, err := p.Encode(&buf, &pt); err != want {
+		t.Fatalf("unexpected error: have=%v want=%v", err, want)
+	} else if buf.Len() != 0 {
+		t.Fatalf("unexpected output: %#v", buf.String())
+	}
+
+	pt.Tags[0].Value = "server01"
+	if _, err := p.Encode(&buf, &pt); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if have, want := buf.String(), "cpu,host=server01 value=5\n"; have != want {
+		t.Errorf("unexpected output: have=%#v want=%#v", have, want)
+	}
+}
+
+func TestAppendPoint(t *testing.T) {
+	pt := influxdb.Point{
+		Name: "cpu load",
+		Tags: []influxdb.Tag{
+			{Key: "host", Value: "server,01"},
+			{Key: "region", Value: "us=east"},
+		},
+		Fields: map[string]interface{}{
+			"value":  float64(5),
+			"status": `running "fast"`,
+		},
+		Time: time.Unix(2, 0),
+	}
+
+	buf, err := influxdb.AppendPoint([]byte("prefix\n"), &pt)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if have, want := string(buf), "prefix\ncpu\\ load,host=server\\,01,region=us\\=east status=\"running \\\"fast\\\"\",value=5 2000000000\n"; have != want {
+		t.Fatalf("unexpected output: have=%#v want=%#v", have, want)
+	}
+
+	// An invalid point should return the original slice unmodified.
+	pt.Fields["value"] = struct{}{}
+	if buf, err := influxdb.AppendPoint([]byte("prefix\n"), &pt); err == nil {
+		t.Fatal("expected error")
+	} else if have, want := string(buf), "prefix\n"; have != want {
+		t.Fatalf("unexpected output: have=%#v want=%#v", have, want)
+	}
+}
+
+// raceEnabled is set when the tests are run with the race detector.
+var raceEnabled bool
+
+func TestAppendPoint_Allocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not reliable with the race detector")
+	}
+
+	pt := influxdb.Point{
+		Name: "cpu",
+		Tags: []influxdb.Tag{
+			{Key: "host", Value: "server01"},
+			{Key: "region", Value: "useast"},
+		},
+		Fields: map[string]interface{}{
+			"value": float64(5),
+			"idle":  int64(95),
+			"state": "running",
+		},
+		Time: time.Unix(25, 0),
+	}
+
+	buf := make([]byte, 0, 1024)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = influxdb.AppendPoint(buf[:0], &pt)
+	})
+	if allocs != 0 {
+		t.Errorf("AppendPoint allocated %v times per run; want 0", allocs)
+	}
+
+	allocs = testing.AllocsPerRun(100, func() {
+		influxdb.Encode(ioutil.Discard, &pt)
+	})
+	if allocs != 0 {
+		t.Errorf("Encode allocated %v times per run; want 0", allocs)
+	}
+}
+
+func BenchmarkAppendPoint(b *testing.B) {
+	pt := influxdb.Point{
+		Name: "cpu",
+		Tags: []influxdb.Tag{
+			{Key: "host", Value: "server01"},
+			{Key: "region", Value: "useast"},
+		},
+		Fields: map[string]interface{}{
+			"value": float64(5),
+			"idle":  int64(95),
+			"state": "running",
+		},
+		Time: time.Unix(25, 0),
+	}
+	buf := make([]byte, 0, 1024)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		buf, _ = influxdb.AppendPoint(buf[:0], &pt)
+	}
+}
+
 func BenchmarkLineProtocol_V1(b *testing.B) {
 	pt := influxdb.Point{
 		Name: "cpu",
@@ -1,6 +1,12 @@
 package influxdb
 
-import "io"
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
 
 // QueryOptions is a set of configuration options for configuring queries.
 type QueryOptions struct {
@@ -11,8 +17,56 @@ type QueryOptions struct {
 	Format    string
 	Async     bool
 	Params    map[string]interface{}
+
+	// FloatNumbers decodes every number in the results as a float64. See
+	// CursorOptions for details.
+	FloatNumbers bool
+
+	// Epoch is the precision of the times returned by the server. If this
+	// is empty, times are returned with nanosecond precision.
+	Epoch Precision
+
+	// RFC3339 causes the server to return times as RFC3339 strings instead
+	// of as an epoch. This includes the offset of any tz() clause in the
+	// query. Epoch is ignored when this is set.
+	RFC3339 bool
+
+	// Statement is the index of the statement in a multi-statement query
+	// whose results are decoded by Query and QueryIter.
+	Statement int
+
+	// ContinueOnError continues with the next statement when a statement in
+	// the query fails. Execute returns an ErrResults with every failure.
+	// See CursorOptions for details.
+	ContinueOnError bool
+
+	// Mode determines whether the query is sent as a readonly GET request
+	// or as a POST request. By default, the mode is chosen by the
+	// statements in the query.
+	Mode QueryMode
 }
 
+// QueryMode determines the kind of HTTP request used to send a query.
+type QueryMode int
+
+const (
+	// QueryModeAuto sends queries that only read from the database, such as
+	// SELECT and SHOW statements, as a readonly GET request. Any other query
+	// is sent as a POST request. Readonly queries that are too long to fit
+	// in a URL are also sent as a POST request.
+	QueryModeAuto QueryMode = iota
+
+	// QueryModeReadonly always sends the query as a readonly GET request.
+	QueryModeReadonly
+
+	// QueryModeWrite always sends the query as a POST request.
+	QueryModeWrite
+)
+
+// maxReadonlyURLLength is the longest URL that is sent as a readonly GET
+// request by QueryModeAuto. Many servers and proxies reject longer URLs.
+const maxReadonlyURLLength = 8000
+
 // Clone creates a copy of the QueryOptions.
 func (opt *QueryOptions) Clone() QueryOptions {
 	clone := *opt
@@ -32,6 +86,12 @@ type Querier struct {
 // Raw executes a raw query returns the unmodified io.ReadCloser from the
 // response if a proper status code is returned.
 func (q *Querier) Raw(query interface{}, opts ...QueryOption) (io.ReadCloser, string, error) {
+	return q.raw(query, q.options(opts))
+}
+
+// options returns the QueryOptions for this Querier with the QueryOption
+// functions applied.
+func (q *Querier) options(opts []QueryOption) QueryOptions {
 	opt := q.QueryOptions
 	if len(opts) > 0 {
 		opt = opt.Clone()
@@ -39,30 +99,107 @@ func (q *Querier) Raw(query interface{}, opts ...QueryOption) (io.ReadCloser, st
 			f.apply(&opt)
 		}
 	}
+	return opt
+}
+
+// newRequest creates the HTTP request for the query using the QueryMode.
+func (q *Querier) newRequest(query interface{}, opt QueryOptions) (*http.Request, error) {
+	switch opt.Mode {
+	case QueryModeReadonly:
+		return q.c.NewReadonlyQueryRequest(query, opt)
+	case QueryModeWrite:
+		return q.c.NewQueryRequest(query, opt)
+	}
+
+	// Read the query into memory so it can be classified. The contents are
+	// still sent as a file if the query is sent as a POST.
+	var text string
+	switch v := query.(type) {
+	case string:
+		text = v
+	case io.Reader:
+		in, err := ioutil.ReadAll(v)
+		if err != nil {
+			return nil, err
+		}
+		text, query = string(in), bytes.NewReader(in)
+	default:
+		return q.c.NewQueryRequest(query, opt)
+	}
+
+	if isReadonlyQuery(text) {
+		req, err := q.c.NewReadonlyQueryRequest(text, opt)
+		if err != nil || len(req.URL.String()) <= maxReadonlyURLLength {
+			return req, err
+		}
+	}
+	return q.c.NewQueryRequest(query, opt)
+}
 
-	req, err := q.c.NewQueryRequest(query, opt)
+// raw executes a raw query with the already resolved QueryOptions.
+func (q *Querier) raw(query interface{}, opt QueryOptions) (io.ReadCloser, string, error) {
+	req, err := q.newRequest(query, opt)
 	if err != nil {
 		return nil, "", err
 	}
 
-	resp, err := q.c.Client.Do(req)
+	log := q.c.logger()
+	start := time.Now()
+	resp, err := q.c.do(OperationQuery, req)
+	elapsed := time.Since(start)
 	if err != nil {
+		log.Warn("query failed", "db", opt.Database, "duration", elapsed, "error", err)
+		return nil, "", err
+	}
+
+	reqID := requestID(resp)
+	if resp.StatusCode/100 != 2 {
+		err := ReadError(resp)
+		log.Warn("query failed", "db", opt.Database, "duration", elapsed, "request_id", reqID, "error", err)
 		return nil, "", err
-	} else if resp.StatusCode/100 != 2 {
-		return nil, "", ReadError(resp)
+	}
+	log.Debug("query", "db", opt.Database, "duration", elapsed, "request_id", reqID)
+	if t := q.c.SlowQueryThreshold; t > 0 && elapsed > t {
+		log.Warn("slow query", "db", opt.Database, "duration", elapsed, "threshold", t, "request_id", reqID)
 	}
 	format := resp.Header.Get("Content-Type")
 	return resp.Body, format, nil
 }
 
 // Select executes a query returns a Cursor that will parse the results from
-// the stream. Use Execute for any queries that modify the database.
+// the stream. The Cursor must be closed when it is no longer needed. Use
+// Execute for any queries that modify the database.
 func (q *Querier) Select(query interface{}, opts ...QueryOption) (*Cursor, error) {
-	r, format, err := q.Raw(query, opts...)
+	opt := q.options(opts)
+
+	// Split the query into statements so each ResultSet can report the
+	// statement that produced it.
+	var text string
+	switch v := query.(type) {
+	case string:
+		text = v
+	case io.Reader:
+		in, err := ioutil.ReadAll(v)
+		if err != nil {
+			return nil, err
+		}
+		text, query = string(in), bytes.NewReader(in)
+	}
+	var statements []string
+	for _, stmt := range SplitStatements(text) {
+		statements = append(statements, stmt.Text)
+	}
+
+	r, format, err := q.raw(query, opt)
 	if err != nil {
 		return nil, err
 	}
-	cur, err := NewCursor(r, format)
+	cur, err := NewCursorOptions(r, format, CursorOptions{
+		FloatNumbers:    opt.FloatNumbers,
+		Epoch:           opt.Epoch,
+		Statements:      statements,
+		ContinueOnError: opt.ContinueOnError,
+	})
 	if err != nil {
 		r.Close()
 		return nil, err
@@ -76,5 +213,6 @@ func (q *Querier) Execute(query interface{}, opts ...QueryOption) error {
 	if err != nil {
 		return err
 	}
+	defer cur.Close()
 	return cur.Each(func(*ResultSet) error { return nil })
 }
@@ -1,18 +1,22 @@
 package influxdb_test
 
 import (
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 
 	influxdb "github.com/influxdata/influxdb-client"
 )
 
 func TestQuerier_Select_Param(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if got, want := r.Method, "POST"; got != want {
+		if got, want := r.Method, "GET"; got != want {
 			t.Errorf("Method = %q; want %q", got, want)
 		}
 
@@ -72,7 +76,7 @@ func TestQuerier_Select_Param(t *testing.T) {
 	})
 
 	exp := [][]interface{}{
-		[]interface{}{"1970-01-01T00:00:00Z", float64(5)},
+		[]interface{}{"1970-01-01T00:00:00Z", int64(5)},
 	}
 	if !reflect.DeepEqual(got, exp) {
 		t.Fatalf("Values = %q; want %q", got, exp)
@@ -91,7 +95,7 @@ func TestQuerier_Select_Param(t *testing.T) {
 
 func TestQuerier_Select_Params(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if got, want := r.Method, "POST"; got != want {
+		if got, want := r.Method, "GET"; got != want {
 			t.Errorf("Method = %q; want %q", got, want)
 		}
 
@@ -154,7 +158,7 @@ func TestQuerier_Select_Params(t *testing.T) {
 	})
 
 	exp := [][]interface{}{
-		[]interface{}{"1970-01-01T00:00:00Z", float64(5)},
+		[]interface{}{"1970-01-01T00:00:00Z", int64(5)},
 	}
 	if !reflect.DeepEqual(got, exp) {
 		t.Fatalf("Values = %q; want %q", got, exp)
@@ -170,3 +174,193 @@ func TestQuerier_Select_Params(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestQuerier_Select_Epoch(t *testing.T) {
+	ts := mustParseTime("2010-01-01T08:00:00+08:00")
+	for _, tt := range []struct {
+		opts  []influxdb.QueryOption
+		epoch string
+		value string
+		want  time.Time
+	}{
+		{
+			epoch: "ns",
+			value: fmt.Sprint(ts.UnixNano()),
+			want:  ts.UTC(),
+		},
+		{
+			opts:  []influxdb.QueryOption{influxdb.Epoch(influxdb.PrecisionMillisecond)},
+			epoch: "ms",
+			value: fmt.Sprint(ts.UnixNano() / int64(time.Millisecond)),
+			want:  ts.UTC(),
+		},
+		{
+			opts:  []influxdb.QueryOption{influxdb.Epoch(influxdb.PrecisionHour)},
+			epoch: "h",
+			value: fmt.Sprint(ts.Unix() / 3600),
+			want:  ts.UTC(),
+		},
+		{
+			opts:  []influxdb.QueryOption{influxdb.RFC3339()},
+			value: `"2010-01-01T08:00:00+08:00"`,
+			want:  ts,
+		},
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if got, want := r.URL.Query().Get("epoch"), tt.epoch; got != want {
+				t.Errorf("epoch = %q; want %q", got, want)
+			}
+
+			w.Header().Add("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"results":[{"series":[{"name":"cpu","columns":["time","value"],"values":[[%s,5]]}]}]}`, tt.value)
+		}))
+
+		client, err := influxdb.NewClient(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cur, err := client.Select("SELECT value FROM cpu", tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := cur.NextSet()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		series, err := result.NextSeries()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		row, err := series.NextRow()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got := row.Time()
+		if !got.Equal(tt.want) {
+			t.Errorf("Time = %v; want %v", got, tt.want)
+		}
+		_, gotOffset := got.Zone()
+		if _, wantOffset := tt.want.Zone(); gotOffset != wantOffset {
+			t.Errorf("Time offset = %d; want %d", gotOffset, wantOffset)
+		}
+		cur.Close()
+		server.Close()
+	}
+}
+
+func TestQuerier_Raw_Mode(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		query interface{}
+		mode  influxdb.QueryMode
+		want  string
+	}{
+		{name: "select", query: "SELECT mean(value) FROM cpu", want: "GET"},
+		{name: "show", query: "SHOW DATABASES", want: "GET"},
+		{name: "create", query: "CREATE DATABASE db0", want: "POST"},
+		{name: "select into", query: "SELECT mean(value) INTO cpu_1h FROM cpu", want: "POST"},
+		{name: "quoted into", query: `SELECT value FROM cpu WHERE host = 'into'; SELECT "into" FROM cpu`, want: "GET"},
+		{name: "multiple statements", query: "SELECT value FROM cpu; DROP MEASUREMENT cpu", want: "POST"},
+		{name: "long select", query: "SELECT value FROM cpu WHERE host = '" + strings.Repeat("a", 8000) + "'", want: "POST"},
+		{name: "reader", query: strings.NewReader("SELECT value FROM cpu"), want: "GET"},
+		{name: "reader write", query: strings.NewReader("DROP DATABASE db0"), want: "POST"},
+		{name: "force readonly", query: "CREATE DATABASE db0", mode: influxdb.QueryModeReadonly, want: "GET"},
+		{name: "force write", query: "SELECT value FROM cpu", mode: influxdb.QueryModeWrite, want: "POST"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got, want := r.Method, tt.want; got != want {
+					t.Errorf("Method = %q; want %q", got, want)
+				}
+				w.Header().Add("Content-Type", "application/json")
+				w.WriteHeader(http.StatusOK)
+				io.WriteString(w, `{"results":[{}]}`)
+			}))
+			defer server.Close()
+
+			client, err := influxdb.NewClient(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r, _, err := client.Raw(tt.query, influxdb.Mode(tt.mode))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			r.Close()
+		})
+	}
+}
+
+func TestQuerier_Select_Statements(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, `{"results":[{"statement_id":0,"series":[{"name":"databases","columns":["name"],"values":[["db0"]]}]},{"statement_id":1,"error":"measurement not found"}]}`)
+	}))
+	defer server.Close()
+
+	client, err := influxdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cur, err := client.Select(strings.NewReader("SHOW DATABASES;\n-- the measurement\nSELECT value FROM cpu WHERE host = 'a;b';\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cur.Close()
+
+	result, err := cur.NextSet()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := result.Statement(), "SHOW DATABASES"; got != want {
+		t.Fatalf("Statement = %q; want %q", got, want)
+	}
+
+	_, err = cur.NextSet()
+	if want := (influxdb.ErrResult{Err: "measurement not found", StatementID: 1, Statement: "SELECT value FROM cpu WHERE host = 'a;b'"}); err != want {
+		t.Fatalf("got error %#v; want %#v", err, want)
+	}
+}
+
+func TestQuerier_Execute_ClosesCursor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, `{"results":[{"statement_id":0,"error":"expected err"},{"statement_id":1,"series":[{"name":"cpu","columns":["time","value"],"values":[[0,1]]}]}]}`)
+	}))
+	defer server.Close()
+
+	client, err := influxdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Make a first request so the connection is established.
+	if err := client.Execute("SELECT 1; SELECT 2"); err == nil {
+		t.Fatal("expected error")
+	}
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 50; i++ {
+		if err := client.Execute("SELECT 1; SELECT 2"); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+
+	// Give the connections a moment to return to the pool.
+	var after int
+	for i := 0; i < 50; i++ {
+		if after = runtime.NumGoroutine(); after <= before+5 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("goroutines = %d after failed queries; want at most %d", after, before+5)
+}
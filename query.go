@@ -0,0 +1,354 @@
+package influxdb
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Query executes a query with the Querier and decodes every row of the
+// selected statement into a T. T must be a struct. Columns and tags are
+// mapped to the exported fields of the struct using the influxdb struct tag:
+//
+//	type CPU struct {
+//		Time  time.Time `influxdb:"time"`
+//		Host  string    `influxdb:"host,tag"`
+//		Value float64   `influxdb:"mean"`
+//		Max   *float64  `influxdb:"max"`
+//		Extra string    `influxdb:"-"`
+//	}
+//
+// A field without a struct tag is mapped to the column with the same name.
+// Column and tag names are compared without case. A field with the tag
+// option is read from the tags of the series. Any other field is read from
+// the column with its name or, if the series has no such column, from the
+// tag with its name. Tags can only be read into string fields. Null values
+// leave the field unchanged. Use a pointer field to tell a null value apart
+// from the zero value. Embedded struct pointers are allocated when one of
+// their fields is set.
+//
+// The first statement is decoded by default. Use the Statement option to
+// decode a different statement in a multi-statement query. Errors from the
+// other statements are ignored. An error is returned if the response has no
+// result for the statement.
+func Query[T any](q *Querier, query interface{}, opts ...QueryOption) ([]T, error) {
+	var out []T
+	for v, err := range QueryIter[T](q, query, opts...) {
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+// QueryIter executes a query the same as Query, but returns an iterator
+// over the decoded rows instead of reading all of them into memory. If the
+// query or decoding a row fails, the error is yielded and the iteration
+// ends. The underlying Cursor is closed when the iteration ends.
+func QueryIter[T any](q *Querier, query interface{}, opts ...QueryOption) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		m, err := structMappingOf(reflect.TypeOf(zero))
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		cur, err := q.Select(query, opts...)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		statement := q.options(opts).Statement
+		defer cur.Close()
+		for {
+			result, err := cur.NextSet()
+			if err != nil {
+				if e, ok := err.(ErrResult); ok && e.StatementID >= 0 && e.StatementID != statement {
+					continue
+				} else if err == io.EOF {
+					err = fmt.Errorf("no result for statement %d", statement)
+				}
+				yield(zero, err)
+				return
+			} else if result.StatementID() != statement {
+				continue
+			}
+
+			for series, err := range result.AllSeries() {
+				if err != nil {
+					yield(zero, err)
+					return
+				}
+
+				dec, err := m.decoder(series)
+				if err != nil {
+					yield(zero, err)
+					return
+				}
+				for row, err := range series.Rows() {
+					if err != nil {
+						yield(zero, err)
+						return
+					}
+
+					var v T
+					if err := dec.decode(reflect.ValueOf(&v).Elem(), row); err != nil {
+						yield(zero, err)
+						return
+					}
+					if !yield(v, nil) {
+						return
+					}
+				}
+			}
+			return
+		}
+	}
+}
+
+// structField is an exported struct field mapped to a column or a tag.
+type structField struct {
+	index []int
+	name  string
+	tag   bool
+}
+
+// structMapping is the mapping of the fields of a struct type.
+type structMapping struct {
+	typ    reflect.Type
+	fields []structField
+}
+
+var structMappings sync.Map // map[reflect.Type]*structMapping
+
+// structMappingOf returns the mapping for the struct type. Mappings are
+// cached for each type.
+func structMappingOf(typ reflect.Type) (*structMapping, error) {
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot decode rows into %v: not a struct", typ)
+	}
+	if m, ok := structMappings.Load(typ); ok {
+		return m.(*structMapping), nil
+	}
+
+	m := &structMapping{typ: typ}
+	for _, f := range reflect.VisibleFields(typ) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+
+		sf := structField{index: f.Index, name: f.Name}
+		if s, ok := f.Tag.Lookup("influxdb"); ok {
+			if s == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(s, ",")
+			if name != "" {
+				sf.name = name
+			}
+			sf.tag = opts == "tag"
+			if sf.tag && indirect(f.Type).Kind() != reflect.String {
+				return nil, fmt.Errorf("cannot decode tag %q into field %s of type %s", sf.name, f.Name, f.Type)
+			}
+		}
+		if err := checkEmbedded(typ, f); err != nil {
+			return nil, err
+		}
+		m.fields = append(m.fields, sf)
+	}
+	actual, _ := structMappings.LoadOrStore(typ, m)
+	return actual.(*structMapping), nil
+}
+
+// fieldDecoder decodes the rows of a single series into a struct.
+type fieldDecoder struct {
+	field  structField
+	column int
+	name   string
+	tag    string
+	hasTag bool
+}
+
+type structDecoder []fieldDecoder
+
+// decoder resolves the columns and tags of the series for every field.
+// Fields that do not map to a column or a tag in the series are skipped.
+// Tags can only be decoded into string fields.
+func (m *structMapping) decoder(series *Series) (structDecoder, error) {
+	columns := series.Columns()
+	tags := series.Tags()
+
+	dec := make(structDecoder, 0, len(m.fields))
+	for _, f := range m.fields {
+		fd := fieldDecoder{field: f, column: -1}
+		if !f.tag {
+			for i, name := range columns {
+				if name == f.name || strings.EqualFold(name, f.name) {
+					fd.column, fd.name = i, name
+					break
+				}
+			}
+		}
+
+		if fd.column < 0 {
+			for _, t := range tags {
+				if t.Key == f.name || strings.EqualFold(t.Key, f.name) {
+					fd.tag, fd.hasTag = t.Value, true
+					break
+				}
+			}
+			if !fd.hasTag {
+				continue
+			}
+
+			if typ := m.typ.FieldByIndex(f.index).Type; indirect(typ).Kind() != reflect.String {
+				return nil, ErrColumnType{Column: f.name, Type: typ.String(), Value: fd.tag}
+			}
+		}
+		dec = append(dec, fd)
+	}
+	return dec, nil
+}
+
+// decode sets the fields of the struct in v from the row.
+func (dec structDecoder) decode(v reflect.Value, row Row) error {
+	for _, fd := range dec {
+		fv := fieldByIndex(v, fd.field.index)
+		if fd.hasTag {
+			fv = allocate(fv)
+			fv.SetString(fd.tag)
+			continue
+		}
+
+		if row.Value(fd.column) == nil {
+			continue
+		}
+		if err := decodeColumn(allocate(fv), row, fd.column, fd.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// decodeColumn sets v to the value of the column in the row using the typed
+// accessors of the Row. The name of the column is used for errors.
+func decodeColumn(v reflect.Value, row Row, column int, name string) error {
+	switch v.Type() {
+	case timeType:
+		t, err := row.TimeAt(column)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := row.Duration(column)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		f, err := row.Float(column)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := row.Int(column)
+		if err != nil {
+			return err
+		} else if v.OverflowInt(n) {
+			return ErrColumnType{Column: name, Type: v.Type().String(), Value: row.Value(column)}
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := row.Uint(column)
+		if err != nil {
+			return err
+		} else if v.OverflowUint(n) {
+			return ErrColumnType{Column: name, Type: v.Type().String(), Value: row.Value(column)}
+		}
+		v.SetUint(n)
+	case reflect.String:
+		s, err := row.String(column)
+		if err != nil {
+			return err
+		}
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := row.Bool(column)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			return ErrColumnType{Column: name, Type: v.Type().String(), Value: row.Value(column)}
+		}
+		v.Set(reflect.ValueOf(row.Value(column)))
+	default:
+		return ErrColumnType{Column: name, Type: v.Type().String(), Value: row.Value(column)}
+	}
+	return nil
+}
+
+// checkEmbedded returns an error if the field is promoted through an
+// embedded pointer that cannot be allocated because it is unexported.
+func checkEmbedded(typ reflect.Type, f reflect.StructField) error {
+	for _, i := range f.Index[:len(f.Index)-1] {
+		ef := typ.Field(i)
+		if ef.Type.Kind() == reflect.Ptr && !ef.IsExported() {
+			return fmt.Errorf("cannot decode rows into field %s through unexported embedded pointer %s", f.Name, ef.Type)
+		}
+		typ = indirect(ef.Type)
+	}
+	return nil
+}
+
+// fieldByIndex returns the nested field of the struct in v. Embedded
+// pointers along the way are allocated if they are nil.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 {
+			v = allocate(v)
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// allocate follows pointers in v, allocating them as needed, and returns
+// the value that is pointed to.
+func allocate(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// indirect returns the type pointed to by typ.
+func indirect(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
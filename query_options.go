@@ -26,3 +26,33 @@ func Params(params map[string]interface{}) QueryOption {
 		}
 	})
 }
+
+// Epoch sets the precision of the times returned by the query.
+func Epoch(precision Precision) QueryOption {
+	return queryOptionFunc(func(opt *QueryOptions) {
+		opt.Epoch = precision
+		opt.RFC3339 = false
+	})
+}
+
+// RFC3339 causes the query to return times as RFC3339 strings.
+func RFC3339() QueryOption {
+	return queryOptionFunc(func(opt *QueryOptions) {
+		opt.RFC3339 = true
+	})
+}
+
+// Statement selects the statement of a multi-statement query whose results
+// are decoded by Query and QueryIter. Statements are indexed from zero.
+func Statement(index int) QueryOption {
+	return queryOptionFunc(func(opt *QueryOptions) {
+		opt.Statement = index
+	})
+}
+
+// Mode sets the QueryMode used to send the query.
+func Mode(mode QueryMode) QueryOption {
+	return queryOptionFunc(func(opt *QueryOptions) {
+		opt.Mode = mode
+	})
+}
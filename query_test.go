@@ -0,0 +1,202 @@
+package influxdb_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb-client"
+)
+
+type cpuRow struct {
+	Time   time.Time `influxdb:"time"`
+	Host   string    `influxdb:"host,tag"`
+	Region string
+	Mean   float64 `influxdb:"mean"`
+	Max    *int64  `influxdb:"max"`
+	Ignore string  `influxdb:"-"`
+	Up     bool
+	Other  interface{} `influxdb:"other"`
+}
+
+func newQueryServer(body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, body)
+	}))
+}
+
+func TestQuery(t *testing.T) {
+	server := newQueryServer(`{"results":[` +
+		`{"series":[{"name":"cpu","tags":{"host":"server01"},"columns":["time","region","mean","max","up","other"],"values":[[0,"uswest",1.5,3,true,"a"],[10,"uswest",2,null,false,null]]},` +
+		`{"name":"cpu","tags":{"host":"server02"},"columns":["time","region","mean","max","up","other"],"values":[[0,"useast",4,5,true,1]]}]},` +
+		`{"series":[{"name":"mem","columns":["time","mean"],"values":[[0,6]]}]}]}`)
+	defer server.Close()
+
+	client, err := influxdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := influxdb.Query[cpuRow](client.Querier(), "SELECT * FROM cpu; SELECT mean FROM mem")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	max := func(v int64) *int64 { return &v }
+	want := []cpuRow{
+		{Time: time.Unix(0, 0).UTC(), Host: "server01", Region: "uswest", Mean: 1.5, Max: max(3), Up: true, Other: "a"},
+		{Time: time.Unix(0, 10).UTC(), Host: "server01", Region: "uswest", Mean: 2},
+		{Time: time.Unix(0, 0).UTC(), Host: "server02", Region: "useast", Mean: 4, Max: max(5), Up: true, Other: int64(1)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v; want %#v", got, want)
+	}
+
+	// Read the second statement instead.
+	got, err = influxdb.Query[cpuRow](client.Querier(), "SELECT * FROM cpu; SELECT mean FROM mem", influxdb.Statement(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := []cpuRow{{Time: time.Unix(0, 0).UTC(), Mean: 6}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v; want %#v", got, want)
+	}
+}
+
+func TestQueryIter_Error(t *testing.T) {
+	server := newQueryServer(`{"results":[{"series":[{"name":"cpu","columns":["time","mean"],"values":[[0,1],[10,"bad"]]}]}]}`)
+	defer server.Close()
+
+	client, err := influxdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		n    int
+		errs []error
+	)
+	for _, err := range influxdb.QueryIter[cpuRow](client.Querier(), "SELECT mean FROM cpu") {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		n++
+	}
+
+	if got, want := n, 1; got != want {
+		t.Fatalf("got %d rows; want %d", got, want)
+	}
+	if want := []error{influxdb.ErrColumnType{Column: "mean", Type: "float64", Value: "bad"}}; !reflect.DeepEqual(errs, want) {
+		t.Fatalf("got errors %#v; want %#v", errs, want)
+	}
+
+	if _, err := influxdb.Query[int](client.Querier(), "SELECT mean FROM cpu"); err == nil {
+		t.Fatal("expected error for a non-struct type")
+	}
+}
+
+func TestQuery_TagFallback(t *testing.T) {
+	server := newQueryServer(`{"results":[{"series":[{"name":"cpu","tags":{"host":"server01","region":"uswest"},"columns":["time","mean"],"values":[[0,1]]}]}]}`)
+	defer server.Close()
+
+	client, err := influxdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type row struct {
+		Host   string
+		Region string
+		Mean   float64
+	}
+	got, err := influxdb.Query[row](client.Querier(), "SELECT mean FROM cpu GROUP BY *")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := []row{{Host: "server01", Region: "uswest", Mean: 1}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v; want %#v", got, want)
+	}
+
+	// A tag cannot be read into a field that is not a string.
+	type badRow struct {
+		Region float64
+	}
+	_, err = influxdb.Query[badRow](client.Querier(), "SELECT mean FROM cpu GROUP BY *")
+	if want := (influxdb.ErrColumnType{Column: "Region", Type: "float64", Value: "uswest"}); err != want {
+		t.Fatalf("got error %#v; want %#v", err, want)
+	}
+}
+
+func TestQuery_Statement(t *testing.T) {
+	server := newQueryServer(`{"results":[` +
+		`{"statement_id":0,"error":"measurement not found"},` +
+		`{"statement_id":1,"series":[{"name":"mem","columns":["time","mean"],"values":[[0,6]]}]}]}`)
+	defer server.Close()
+
+	client, err := influxdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const query = "SELECT * FROM cpu; SELECT mean FROM mem"
+	got, err := influxdb.Query[cpuRow](client.Querier(), query, influxdb.Statement(1))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := []cpuRow{{Time: time.Unix(0, 0).UTC(), Mean: 6}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v; want %#v", got, want)
+	}
+
+	_, err = influxdb.Query[cpuRow](client.Querier(), query)
+	if e, ok := err.(influxdb.ErrResult); !ok || e.StatementID != 0 {
+		t.Fatalf("got error %#v; want an ErrResult for statement 0", err)
+	}
+
+	if _, err := influxdb.Query[cpuRow](client.Querier(), query, influxdb.Statement(2)); err == nil {
+		t.Fatal("expected error for a statement without a result")
+	}
+}
+
+type queryBase struct {
+	Host string
+}
+
+type QueryBase struct {
+	Host string
+}
+
+func TestQuery_EmbeddedPointer(t *testing.T) {
+	server := newQueryServer(`{"results":[{"series":[{"name":"cpu","tags":{"host":"server01"},"columns":["time","mean"],"values":[[0,1]]}]}]}`)
+	defer server.Close()
+
+	client, err := influxdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type row struct {
+		*QueryBase
+		Mean float64
+	}
+	got, err := influxdb.Query[row](client.Querier(), "SELECT mean FROM cpu GROUP BY *")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := []row{{QueryBase: &QueryBase{Host: "server01"}, Mean: 1}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v; want %#v", got, want)
+	}
+
+	// An unexported embedded pointer cannot be allocated.
+	type badRow struct {
+		*queryBase
+		Mean float64
+	}
+	if _, err := influxdb.Query[badRow](client.Querier(), "SELECT mean FROM cpu GROUP BY *"); err == nil {
+		t.Fatal("expected error for an unexported embedded pointer")
+	}
+}
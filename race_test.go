@@ -0,0 +1,9 @@
+//go:build race
+
+package influxdb_test
+
+func init() {
+	// The race detector randomly drops items from a sync.Pool so allocation
+	// counts are not reliable.
+	raceEnabled = true
+}
@@ -0,0 +1,123 @@
+package influxdb
+
+import (
+	"math"
+	"time"
+)
+
+// Nullable converts the result of one of the typed Row accessors so a null
+// value is reported by ok being false instead of with an ErrNullValue. Any
+// other error is returned unchanged.
+//
+//	v, ok, err := influxdb.Nullable(row.FloatByName("mean"))
+func Nullable[T any](v T, err error) (_ T, ok bool, _ error) {
+	if err != nil {
+		if _, null := err.(ErrNullValue); null {
+			return v, false, nil
+		}
+		return v, false, err
+	}
+	return v, true, nil
+}
+
+// convertColumn converts a value read from a column using the conversion
+// function. The err is passed through if the column could not be read.
+func convertColumn[T any](v interface{}, column string, err error, typ string, convert func(interface{}) (T, bool)) (T, error) {
+	var zero T
+	if err != nil {
+		return zero, err
+	} else if v == nil {
+		return zero, ErrNullValue{Column: column}
+	}
+
+	out, ok := convert(v)
+	if !ok {
+		return zero, ErrColumnType{Column: column, Type: typ, Value: v}
+	}
+	return out, nil
+}
+
+func asFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+func asInt(v interface{}) (int64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return v, true
+	case uint64:
+		if v <= math.MaxInt64 {
+			return int64(v), true
+		}
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
+
+func asUint(v interface{}) (uint64, bool) {
+	switch v := v.(type) {
+	case uint64:
+		return v, true
+	case int64:
+		if v >= 0 {
+			return uint64(v), true
+		}
+	case float64:
+		if v == math.Trunc(v) && v >= 0 && v < math.MaxUint64 {
+			return uint64(v), true
+		}
+	}
+	return 0, false
+}
+
+func asString(v interface{}) (string, bool) {
+	s, ok := v.(string)
+	return s, ok
+}
+
+func asBool(v interface{}) (bool, bool) {
+	b, ok := v.(bool)
+	return b, ok
+}
+
+// asTime returns a conversion function for times that are either an
+// RFC3339Nano string or a number of epoch units since the epoch.
+func asTime(epoch time.Duration) func(interface{}) (time.Time, bool) {
+	return func(v interface{}) (time.Time, bool) {
+		switch v := v.(type) {
+		case string:
+			// This also accepts RFC3339 without nanoseconds.
+			t, err := time.Parse(time.RFC3339Nano, v)
+			return t, err == nil
+		case int64:
+			return time.Unix(0, v*int64(epoch)).UTC(), true
+		case float64:
+			return time.Unix(0, int64(v)*int64(epoch)).UTC(), true
+		}
+		return time.Time{}, false
+	}
+}
+
+func asDuration(v interface{}) (time.Duration, bool) {
+	switch v := v.(type) {
+	case string:
+		d, err := time.ParseDuration(v)
+		return d, err == nil
+	case int64:
+		return time.Duration(v), true
+	case float64:
+		return time.Duration(v), true
+	}
+	return 0, false
+}
@@ -0,0 +1,77 @@
+package influxdb
+
+import (
+	"io"
+	"time"
+)
+
+// SeriesWriter writes points for a single series to an io.Writer. The
+// measurement name and tags are escaped once when the SeriesWriter is created
+// so each write only needs to encode the fields and the time. This is useful
+// when writing the same series at a high rate.
+//
+// The points are encoded with the Protocol of the io.Writer when it is a
+// Writer, or the default line protocol otherwise. The precision of that
+// Protocol is honored. If the Protocol is not the line protocol, each write
+// falls back to encoding a full Point with that Protocol.
+//
+// A SeriesWriter is not safe for concurrent use.
+type SeriesWriter struct {
+	w    io.Writer
+	p    Protocol
+	name string
+	tags Tags
+
+	// key is the precomputed series key including the space that separates
+	// it from the fields. It is only set when the points can be encoded by
+	// the line protocol in lp.
+	lp  *lineProtocolV1
+	key []byte
+	buf []byte
+}
+
+// NewSeriesWriter creates a new SeriesWriter for the series identified by the
+// measurement name and tags.
+func NewSeriesWriter(w io.Writer, name string, tags Tags) *SeriesWriter {
+	p := DefaultWriteProtocol
+	if w, ok := w.(Writer); ok {
+		if wp := w.Protocol(); wp != nil {
+			p = wp
+		}
+	}
+
+	sw := &SeriesWriter{
+		w:    w,
+		p:    p,
+		name: name,
+		tags: tags,
+	}
+	if lp, ok := p.(*lineProtocolV1); ok && (lp == nil || !lp.strict) {
+		sw.lp = lp
+		sw.key = lp.appendSeriesKey(nil, name, tags)
+		sw.key = append(sw.key, ' ')
+	}
+	return sw
+}
+
+// Protocol returns the Protocol that points are encoded with.
+func (sw *SeriesWriter) Protocol() Protocol {
+	return sw.p
+}
+
+// WritePoint writes a point for this series with the fields and time. If the
+// time is zero, the point is written without a time so the server assigns one.
+func (sw *SeriesWriter) WritePoint(fields map[string]interface{}, t time.Time) (n int, err error) {
+	if sw.key == nil {
+		pt := Point{Name: sw.name, Tags: sw.tags, Fields: fields, Time: t}
+		return sw.p.Encode(sw.w, &pt)
+	}
+
+	buf := append(sw.buf[:0], sw.key...)
+	buf, err = sw.lp.appendFields(buf, fields, t)
+	sw.buf = buf
+	if err != nil {
+		return 0, err
+	}
+	return sw.w.Write(buf)
+}
@@ -0,0 +1,92 @@
+package influxdb_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb-client"
+)
+
+func TestSeriesWriter(t *testing.T) {
+	var buf bytes.Buffer
+	sw := influxdb.NewSeriesWriter(&buf, "cpu load", influxdb.Tags{
+		{Key: "region", Value: "us east"},
+		{Key: "host", Value: "server01"},
+	})
+
+	if _, err := sw.WritePoint(map[string]interface{}{"value": 5.0, "idle": int64(95)}, time.Unix(10, 0)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := sw.WritePoint(map[string]interface{}{"value": 6.0}, time.Time{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := sw.WritePoint(nil, time.Time{}); err != influxdb.ErrNoFields {
+		t.Fatalf("unexpected error: have=%v want=%v", err, influxdb.ErrNoFields)
+	}
+
+	want := "cpu\\ load,host=server01,region=us\\ east idle=95i,value=5 10000000000\n" +
+		"cpu\\ load,host=server01,region=us\\ east value=6\n"
+	if have := buf.String(); have != want {
+		t.Fatalf("unexpected output: have=%#v want=%#v", have, want)
+	}
+}
+
+func TestSeriesWriter_Precision(t *testing.T) {
+	var buf bytes.Buffer
+	w := influxdb.NewBufferedWriter(&buf)
+	tests := []struct {
+		protocol influxdb.Protocol
+		want     string
+	}{
+		{
+			protocol: influxdb.WithPrecision(influxdb.DefaultWriteProtocol, influxdb.PrecisionSecond),
+			want:     "cpu,host=server01 value=5 10\n",
+		},
+		{
+			protocol: influxdb.WithPrecision(customProtocol{}, influxdb.PrecisionSecond),
+			want:     "cpu,host=server01 value=5 10000000000\n",
+		},
+	}
+
+	for i, tt := range tests {
+		pw := &protocolWriter{Writer: w, p: tt.protocol}
+		sw := influxdb.NewSeriesWriter(pw, "cpu", influxdb.Tags{{Key: "host", Value: "server01"}})
+		if _, err := sw.WritePoint(map[string]interface{}{"value": 5.0}, time.Unix(10, 500000000)); err != nil {
+			t.Fatalf("%d. unexpected error: %s", i, err)
+		} else if err := w.Flush(); err != nil {
+			t.Fatalf("%d. unexpected error: %s", i, err)
+		}
+
+		if have := buf.String(); have != tt.want {
+			t.Errorf("%d. unexpected output: have=%#v want=%#v", i, have, tt.want)
+		}
+		buf.Reset()
+	}
+}
+
+func BenchmarkSeriesWriter(b *testing.B) {
+	var buf bytes.Buffer
+	sw := influxdb.NewSeriesWriter(&buf, "cpu", influxdb.Tags{
+		{Key: "host", Value: "server01"},
+		{Key: "region", Value: "useast"},
+	})
+	fields := map[string]interface{}{"value": float64(5)}
+	ts := time.Unix(25, 0)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		sw.WritePoint(fields, ts)
+	}
+}
+
+// protocolWriter overrides the Protocol of a Writer.
+type protocolWriter struct {
+	influxdb.Writer
+	p influxdb.Protocol
+}
+
+func (w *protocolWriter) Protocol() influxdb.Protocol {
+	return w.p
+}
@@ -0,0 +1,232 @@
+package influxdb
+
+import (
+	"expvar"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// latencyBuckets are the upper bounds of the latency histogram buckets. The
+// final bucket holds any latency above the last bound.
+var latencyBuckets = [...]time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// ClientStats is a snapshot of the statistics collected by a Client.
+type ClientStats struct {
+	// Queries, Writes and Pings hold the statistics for each kind of request.
+	Queries OperationStats
+	Writes  OperationStats
+	Pings   OperationStats
+
+	// PointsWritten and BytesWritten count the line protocol points and the
+	// bytes sent by successful or partial writes.
+	PointsWritten int64
+	BytesWritten  int64
+
+	// PartialWrites is the number of writes the server only partially
+	// accepted.
+	PartialWrites int64
+
+	// ErrorsByStatus counts the responses with a status code that was not
+	// successful, keyed by the status code.
+	ErrorsByStatus map[int]int64
+}
+
+// OperationStats holds the statistics for one kind of request.
+type OperationStats struct {
+	// Requests is the number of requests that were sent.
+	Requests int64
+
+	// Errors is the number of requests that failed, either because the
+	// request could not be sent or because the status code was not
+	// successful.
+	Errors int64
+
+	// Latency is the histogram of the time it took to receive a response.
+	Latency LatencyHistogram
+}
+
+// LatencyHistogram is a histogram of request latencies.
+type LatencyHistogram struct {
+	// Buckets holds the number of requests in each bucket. The last bucket
+	// has an UpperBound of zero and holds any latency above the previous
+	// bucket.
+	Buckets []LatencyBucket
+
+	// Count is the total number of requests in the histogram.
+	Count int64
+
+	// Sum is the sum of all of the latencies in the histogram.
+	Sum time.Duration
+}
+
+// LatencyBucket is a single bucket of a LatencyHistogram. It counts the
+// requests with a latency less than or equal to UpperBound and greater than
+// the UpperBound of the previous bucket.
+type LatencyBucket struct {
+	UpperBound time.Duration
+	Count      int64
+}
+
+// Mean returns the mean latency.
+func (h LatencyHistogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Stats returns a snapshot of the statistics collected by this client.
+func (c *Client) Stats() ClientStats {
+	return c.statistics().snapshot()
+}
+
+// PublishExpvar publishes the statistics of this client with the expvar
+// package under the given name. Like expvar.Publish, this panics if the name
+// has already been published.
+func (c *Client) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return c.Stats()
+	}))
+}
+
+// statistics returns the statistics of this client, allocating them on first
+// use. The pointer is swapped in atomically instead of being guarded by a
+// lock so a Client can still be copied.
+func (c *Client) statistics() *clientStats {
+	if p := atomic.LoadPointer(&c.stats); p != nil {
+		return (*clientStats)(p)
+	}
+	atomic.CompareAndSwapPointer(&c.stats, nil, unsafe.Pointer(&clientStats{}))
+	return (*clientStats)(atomic.LoadPointer(&c.stats))
+}
+
+// clientStats collects the statistics for a Client.
+type clientStats struct {
+	mu             sync.Mutex
+	queries        operationStats
+	writes         operationStats
+	pings          operationStats
+	pointsWritten  int64
+	bytesWritten   int64
+	partialWrites  int64
+	errorsByStatus map[int]int64
+}
+
+type operationStats struct {
+	requests int64
+	errors   int64
+	buckets  [len(latencyBuckets) + 1]int64
+	sum      time.Duration
+}
+
+// recordRequest records the outcome of a request.
+func (s *clientStats) recordRequest(op Operation, resp *http.Response, err error, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var stats *operationStats
+	switch op {
+	case OperationQuery:
+		stats = &s.queries
+	case OperationWrite:
+		stats = &s.writes
+	case OperationPing:
+		stats = &s.pings
+	default:
+		return
+	}
+
+	stats.requests++
+	stats.sum += d
+	i := 0
+	for i < len(latencyBuckets) && d > latencyBuckets[i] {
+		i++
+	}
+	stats.buckets[i]++
+
+	if err != nil {
+		stats.errors++
+	} else if resp.StatusCode/100 != 2 {
+		stats.errors++
+		if s.errorsByStatus == nil {
+			s.errorsByStatus = make(map[int]int64)
+		}
+		s.errorsByStatus[resp.StatusCode]++
+	}
+}
+
+// recordWrite records the points and bytes sent by a write.
+func (s *clientStats) recordWrite(data []byte, partial bool) {
+	points := 0
+	for _, b := range data {
+		if b == '\n' {
+			points++
+		}
+	}
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		points++
+	}
+
+	s.mu.Lock()
+	s.pointsWritten += int64(points)
+	s.bytesWritten += int64(len(data))
+	if partial {
+		s.partialWrites++
+	}
+	s.mu.Unlock()
+}
+
+func (s *clientStats) snapshot() ClientStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := ClientStats{
+		Queries:       s.queries.snapshot(),
+		Writes:        s.writes.snapshot(),
+		Pings:         s.pings.snapshot(),
+		PointsWritten: s.pointsWritten,
+		BytesWritten:  s.bytesWritten,
+		PartialWrites: s.partialWrites,
+	}
+	if len(s.errorsByStatus) > 0 {
+		stats.ErrorsByStatus = make(map[int]int64, len(s.errorsByStatus))
+		for code, n := range s.errorsByStatus {
+			stats.ErrorsByStatus[code] = n
+		}
+	}
+	return stats
+}
+
+func (s *operationStats) snapshot() OperationStats {
+	buckets := make([]LatencyBucket, len(s.buckets))
+	for i, n := range s.buckets {
+		buckets[i].Count = n
+		if i < len(latencyBuckets) {
+			buckets[i].UpperBound = latencyBuckets[i]
+		}
+	}
+	return OperationStats{
+		Requests: s.requests,
+		Errors:   s.errors,
+		Latency: LatencyHistogram{
+			Buckets: buckets,
+			Count:   s.requests,
+			Sum:     s.sum,
+		},
+	}
+}
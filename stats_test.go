@@ -0,0 +1,132 @@
+package influxdb_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	influxdb "github.com/influxdata/influxdb-client"
+)
+
+func TestClient_Stats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/write":
+			if r.URL.Query().Get("db") == "partial" {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				io.WriteString(w, `{"error":"partial write: points beyond retention policy dropped=1"}`)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case "/query":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"error":"database not found: db0"}`)
+		case "/ping":
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client, err := influxdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer := client.Writer()
+	if _, err := writer.Write([]byte("cpu value=1\ncpu value=2\n")); err != nil {
+		t.Fatal(err)
+	}
+	writer.Database = "partial"
+	if _, err := writer.Write([]byte("cpu value=3\n")); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := client.Ping(); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Execute("SELECT value FROM cpu"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	stats := client.Stats()
+	if have, want := stats.Writes.Requests, int64(2); have != want {
+		t.Errorf("Writes.Requests = %d; want %d", have, want)
+	}
+	if have, want := stats.Writes.Errors, int64(1); have != want {
+		t.Errorf("Writes.Errors = %d; want %d", have, want)
+	}
+	if have, want := stats.Queries.Errors, int64(1); have != want {
+		t.Errorf("Queries.Errors = %d; want %d", have, want)
+	}
+	if have, want := stats.Pings.Requests, int64(1); have != want {
+		t.Errorf("Pings.Requests = %d; want %d", have, want)
+	}
+	if have, want := stats.PointsWritten, int64(3); have != want {
+		t.Errorf("PointsWritten = %d; want %d", have, want)
+	}
+	if have, want := stats.BytesWritten, int64(36); have != want {
+		t.Errorf("BytesWritten = %d; want %d", have, want)
+	}
+	if have, want := stats.PartialWrites, int64(1); have != want {
+		t.Errorf("PartialWrites = %d; want %d", have, want)
+	}
+	if have, want := stats.ErrorsByStatus[http.StatusBadRequest], int64(1); have != want {
+		t.Errorf("ErrorsByStatus[400] = %d; want %d", have, want)
+	}
+	if have, want := stats.ErrorsByStatus[http.StatusNotFound], int64(1); have != want {
+		t.Errorf("ErrorsByStatus[404] = %d; want %d", have, want)
+	}
+
+	var n int64
+	for _, b := range stats.Writes.Latency.Buckets {
+		n += b.Count
+	}
+	if have, want := n, stats.Writes.Latency.Count; have != want {
+		t.Errorf("sum of bucket counts = %d; want %d", have, want)
+	}
+
+	client.PublishExpvar("influxdb_client_test")
+	var published influxdb.ClientStats
+	if err := json.Unmarshal([]byte(expvar.Get("influxdb_client_test").String()), &published); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if have, want := published.Writes.Requests, int64(2); have != want {
+		t.Errorf("published Writes.Requests = %d; want %d", have, want)
+	}
+}
+
+func TestClient_Stats_Literal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The statistics of a Client created without NewClient are allocated
+	// by the first request, which may happen from several goroutines.
+	client := &influxdb.Client{Addr: u.Host}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Ping(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if have, want := client.Stats().Pings.Requests, int64(8); have != want {
+		t.Errorf("Pings.Requests = %d; want %d", have, want)
+	}
+}
@@ -0,0 +1,285 @@
+package influxdb
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTCPPoolSize     = 4
+	defaultTCPIdleTimeout  = 60 * time.Second
+	defaultTCPDialTimeout  = 5 * time.Second
+	defaultTCPWriteTimeout = 10 * time.Second
+	defaultTCPMaxRetries   = 3
+	defaultTCPMinBackoff   = 100 * time.Millisecond
+	defaultTCPMaxBackoff   = 5 * time.Second
+
+	// tcpCheckIdle is how long a connection must be idle before it is
+	// checked for liveness. Checking waits briefly for a read, so it is
+	// skipped for connections that are reused often.
+	tcpCheckIdle = 100 * time.Millisecond
+)
+
+// TCPOptions is a set of configuration options for configuring a TCPWriter.
+// Any zero values are replaced with reasonable defaults.
+type TCPOptions struct {
+	// Protocol is the protocol points will be encoded with. The precision of
+	// the protocol should match the precision the listener expects.
+	Protocol Protocol
+
+	// PoolSize is the maximum number of idle connections that will be kept
+	// open for reuse.
+	PoolSize int
+
+	// IdleTimeout is the maximum amount of time a connection can stay idle
+	// in the pool before it is closed instead of being reused.
+	IdleTimeout time.Duration
+
+	// DialTimeout is the maximum amount of time a dial will wait for a
+	// connection to be established.
+	DialTimeout time.Duration
+
+	// WriteTimeout is the deadline for a single Write to finish sending its
+	// data on a connection.
+	WriteTimeout time.Duration
+
+	// MaxRetries is the number of times a failed Write will reconnect and
+	// retry before giving up.
+	MaxRetries int
+
+	// MinBackoff and MaxBackoff bound the delay between reconnect attempts.
+	// The delay starts at MinBackoff and doubles after each failed attempt
+	// until it reaches MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Logger receives debug and warning events for reconnects and retries.
+	// If this is left nil, nothing is logged.
+	Logger Logger
+}
+
+var _ Writer = &TCPWriter{}
+
+// TCPWriter writes line protocol to a raw TCP socket such as the socket
+// listener in Telegraf. It is safe for concurrent use. Each call to Write
+// uses a single connection from a pool so the data from concurrent writes is
+// never interleaved.
+//
+// If a connection fails in the middle of a Write, the connection is discarded
+// and the write is resumed on a new connection starting from the beginning of
+// the first line that was not completely written. That line is re-sent whole,
+// but the listener may have already received the start of it on the failed
+// connection. Data that was accepted by the operating system before the
+// connection failed may also never reach the listener. The data passed to
+// Write should always end on a line boundary, which is what a BufferedWriter
+// guarantees.
+//
+// Connections that have been idle in the pool for more than a short time are
+// checked before they are reused and are discarded if the listener has closed
+// them. Connections that have been idle for longer than the IdleTimeout are
+// always discarded.
+type TCPWriter struct {
+	addr string
+	opt  TCPOptions
+
+	mu     sync.Mutex
+	idle   []idleConn
+	closed bool
+}
+
+// idleConn is a connection in the pool along with the time it was returned.
+type idleConn struct {
+	conn  net.Conn
+	since time.Time
+}
+
+// NewTCPWriter creates a new TCPWriter that will connect to the specified
+// address and will be encoded with the DefaultWriteProtocol.
+func NewTCPWriter(addr string) (*TCPWriter, error) {
+	return NewTCPWriterOptions(addr, TCPOptions{})
+}
+
+// NewTCPWriterOptions creates a new TCPWriter that will connect to the
+// specified address using the given options. An initial connection is made
+// to verify the address is reachable.
+func NewTCPWriterOptions(addr string, opt TCPOptions) (*TCPWriter, error) {
+	if opt.Protocol == nil {
+		opt.Protocol = DefaultWriteProtocol
+	}
+	if opt.PoolSize <= 0 {
+		opt.PoolSize = defaultTCPPoolSize
+	}
+	if opt.IdleTimeout <= 0 {
+		opt.IdleTimeout = defaultTCPIdleTimeout
+	}
+	if opt.DialTimeout <= 0 {
+		opt.DialTimeout = defaultTCPDialTimeout
+	}
+	if opt.WriteTimeout <= 0 {
+		opt.WriteTimeout = defaultTCPWriteTimeout
+	}
+	if opt.MaxRetries <= 0 {
+		opt.MaxRetries = defaultTCPMaxRetries
+	}
+	if opt.MinBackoff <= 0 {
+		opt.MinBackoff = defaultTCPMinBackoff
+	}
+	if opt.MaxBackoff < opt.MinBackoff {
+		opt.MaxBackoff = defaultTCPMaxBackoff
+		if opt.MaxBackoff < opt.MinBackoff {
+			opt.MaxBackoff = opt.MinBackoff
+		}
+	}
+
+	opt.Logger = loggerOrNop(opt.Logger)
+
+	w := &TCPWriter{addr: addr, opt: opt}
+	conn, err := w.dial()
+	if err != nil {
+		return nil, err
+	}
+	w.put(conn)
+	return w, nil
+}
+
+// Write writes the data to the TCP socket. If the connection fails, Write
+// reconnects with an exponential backoff and continues from the first line
+// that was not completely written. The returned count only includes lines
+// that were written in full.
+func (w *TCPWriter) Write(data []byte) (n int, err error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	backoff := w.opt.MinBackoff
+	for attempt := 0; ; attempt++ {
+		var conn net.Conn
+		conn, err = w.get()
+		if err == nil {
+			var m int
+			m, err = w.write(conn, data[n:])
+			if err == nil {
+				w.put(conn)
+				return len(data), nil
+			}
+			conn.Close()
+
+			// Only count the lines that made it through in full. The
+			// remainder of a partially written line will be written again
+			// from its start.
+			if i := bytes.LastIndexByte(data[n:n+m], '\n'); i >= 0 {
+				n += i + 1
+			}
+		}
+
+		if err == ErrWriterClosed || attempt >= w.opt.MaxRetries {
+			w.opt.Logger.Warn("tcp write failed", "addr", w.addr, "attempts", attempt+1, "written_bytes", n, "bytes", len(data), "error", err)
+			return n, err
+		}
+		w.opt.Logger.Debug("tcp write failed, reconnecting", "addr", w.addr, "attempt", attempt+1, "backoff", backoff, "error", err)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > w.opt.MaxBackoff {
+			backoff = w.opt.MaxBackoff
+		}
+	}
+}
+
+// write writes the data to the connection within the write deadline.
+func (w *TCPWriter) write(conn net.Conn, data []byte) (n int, err error) {
+	if err := conn.SetWriteDeadline(time.Now().Add(w.opt.WriteTimeout)); err != nil {
+		return 0, err
+	}
+	return conn.Write(data)
+}
+
+// get retrieves an idle connection from the pool or dials a new one. Idle
+// connections that have expired or have been closed by the listener are
+// discarded.
+func (w *TCPWriter) get() (net.Conn, error) {
+	for {
+		w.mu.Lock()
+		if w.closed {
+			w.mu.Unlock()
+			return nil, ErrWriterClosed
+		}
+		n := len(w.idle)
+		if n == 0 {
+			w.mu.Unlock()
+			return w.dial()
+		}
+		ic := w.idle[n-1]
+		w.idle = w.idle[:n-1]
+		w.mu.Unlock()
+
+		idle := time.Since(ic.since)
+		if idle <= w.opt.IdleTimeout && (idle < tcpCheckIdle || alive(ic.conn)) {
+			return ic.conn, nil
+		}
+		w.opt.Logger.Debug("tcp idle connection discarded", "addr", w.addr, "idle", idle)
+		ic.conn.Close()
+	}
+}
+
+// alive reports whether an idle connection is still open. Nothing is
+// expected to be read from the listener, so a read that does not time out
+// means the connection was closed by the listener.
+func alive(conn net.Conn) bool {
+	if err := conn.SetReadDeadline(time.Now().Add(time.Millisecond)); err != nil {
+		return false
+	}
+	var buf [1]byte
+	_, err := conn.Read(buf[:])
+	if e := conn.SetReadDeadline(time.Time{}); e != nil {
+		return false
+	}
+
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// put returns a connection to the pool. If the pool is full or the writer
+// has been closed, the connection is closed instead.
+func (w *TCPWriter) put(conn net.Conn) {
+	w.mu.Lock()
+	if w.closed || len(w.idle) >= w.opt.PoolSize {
+		w.mu.Unlock()
+		conn.Close()
+		return
+	}
+	w.idle = append(w.idle, idleConn{conn: conn, since: time.Now()})
+	w.mu.Unlock()
+}
+
+// dial opens a new connection to the address.
+func (w *TCPWriter) dial() (net.Conn, error) {
+	return net.DialTimeout("tcp", w.addr, w.opt.DialTimeout)
+}
+
+// Protocol returns the protocol associated with this TCP writer.
+func (w *TCPWriter) Protocol() Protocol {
+	return w.opt.Protocol
+}
+
+// Close closes all of the idle connections in the pool. Connections that are
+// in use are closed when the Write using them returns. Any calls to Write
+// after Close will return ErrWriterClosed.
+func (w *TCPWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	var err error
+	for _, ic := range w.idle {
+		if e := ic.conn.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	w.idle = nil
+	return err
+}
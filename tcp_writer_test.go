@@ -0,0 +1,256 @@
+package influxdb_test
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	influxdb "github.com/influxdata/influxdb-client"
+)
+
+func TestTCPWriter_WritePoints(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	type resp struct {
+		lines []string
+		err   error
+	}
+	in := make(chan resp, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			in <- resp{err: err}
+			return
+		}
+		defer conn.Close()
+
+		var lines []string
+		scanner := bufio.NewScanner(conn)
+		for len(lines) < 2 && scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		in <- resp{lines: lines, err: scanner.Err()}
+	}()
+
+	protocol := influxdb.WithPrecision(influxdb.DefaultWriteProtocol, influxdb.PrecisionSecond)
+	w, err := influxdb.NewTCPWriterOptions(l.Addr().String(), influxdb.TCPOptions{Protocol: protocol})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	pt := influxdb.Point{
+		Name:   "cpu",
+		Tags:   influxdb.Tags{{Key: "host", Value: "server01"}},
+		Fields: map[string]interface{}{"value": 5.0},
+		Time:   time.Unix(10, 0),
+	}
+
+	// Both writes should reuse the same pooled connection.
+	for i := 0; i < 2; i++ {
+		if _, err := influxdb.WritePoints(w, []influxdb.Point{pt}); err != nil {
+			t.Fatalf("unable to write tcp message: %s", err)
+		}
+	}
+
+	timer := time.NewTimer(time.Second)
+	select {
+	case <-timer.C:
+		t.Fatal("no tcp message received")
+	case r := <-in:
+		timer.Stop()
+
+		if r.err != nil {
+			t.Fatalf("error reading from tcp socket: %s", r.err)
+		} else if got, want := len(r.lines), 2; got != want {
+			t.Fatalf("unexpected number of lines: got=%d want=%d", got, want)
+		}
+		for _, line := range r.lines {
+			if got, want := line, "cpu,host=server01 value=5 10"; got != want {
+				t.Fatalf("unexpected tcp message: got=%q want=%q", got, want)
+			}
+		}
+	}
+}
+
+func TestTCPWriter_Reconnect(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	type resp struct {
+		data []byte
+		err  error
+	}
+	in := make(chan resp, 1)
+	go func() {
+		// Drop the first connection after reading part of the write.
+		conn, err := l.Accept()
+		if err != nil {
+			in <- resp{err: err}
+			return
+		}
+		if _, err := io.ReadFull(conn, make([]byte, 1024)); err != nil {
+			conn.Close()
+			in <- resp{err: err}
+			return
+		}
+		conn.(*net.TCPConn).SetLinger(0)
+		conn.Close()
+
+		conn, err = l.Accept()
+		if err != nil {
+			in <- resp{err: err}
+			return
+		}
+		defer conn.Close()
+
+		data, err := io.ReadAll(conn)
+		in <- resp{data: data, err: err}
+	}()
+
+	w, err := influxdb.NewTCPWriterOptions(l.Addr().String(), influxdb.TCPOptions{MinBackoff: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Write enough data that the write is still in progress when the first
+	// connection is dropped.
+	var buf bytes.Buffer
+	for i := 0; buf.Len() < 32<<20; i++ {
+		fmt.Fprintf(&buf, "cpu value=%d\n", i)
+	}
+	data := buf.Bytes()
+
+	n, err := w.Write(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if got, want := n, len(data); got != want {
+		t.Fatalf("unexpected number of bytes written: got=%d want=%d", got, want)
+	}
+	w.Close()
+
+	r := <-in
+	if r.err != nil {
+		t.Fatalf("error reading from tcp socket: %s", r.err)
+	}
+
+	// The new connection resumes the write from the start of a line and
+	// does not send the lines that were written before the failure again.
+	if !bytes.HasSuffix(data, r.data) {
+		t.Fatal("new connection did not receive the end of the data")
+	}
+	offset := len(data) - len(r.data)
+	if offset == 0 {
+		t.Fatal("new connection received the data from the beginning")
+	} else if data[offset-1] != '\n' {
+		t.Fatalf("new connection did not start on a line boundary: %q", r.data[:20])
+	}
+}
+
+func TestTCPWriter_IdleConnectionClosed(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	type resp struct {
+		line string
+		err  error
+	}
+	closed := make(chan struct{})
+	in := make(chan resp, 1)
+	go func() {
+		// Close the connection that is idle in the pool.
+		conn, err := l.Accept()
+		if err != nil {
+			in <- resp{err: err}
+			return
+		}
+		conn.Close()
+		close(closed)
+
+		conn, err = l.Accept()
+		if err != nil {
+			in <- resp{err: err}
+			return
+		}
+		defer conn.Close()
+
+		scanner := bufio.NewScanner(conn)
+		scanner.Scan()
+		in <- resp{line: scanner.Text(), err: scanner.Err()}
+	}()
+
+	w, err := influxdb.NewTCPWriter(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// Wait long enough for the idle connection to be checked before reuse.
+	<-closed
+	time.Sleep(200 * time.Millisecond)
+
+	if _, err := w.Write([]byte("cpu value=5\n")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	timer := time.NewTimer(time.Second)
+	select {
+	case <-timer.C:
+		t.Fatal("no tcp message received")
+	case r := <-in:
+		timer.Stop()
+
+		if r.err != nil {
+			t.Fatalf("error reading from tcp socket: %s", r.err)
+		} else if got, want := r.line, "cpu value=5"; got != want {
+			t.Fatalf("unexpected tcp message: got=%q want=%q", got, want)
+		}
+	}
+}
+
+func TestTCPWriter_Closed(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	w, err := influxdb.NewTCPWriter(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := w.Write([]byte("cpu value=5\n")); err != influxdb.ErrWriterClosed {
+		t.Fatalf("unexpected error: got=%v want=%v", err, influxdb.ErrWriterClosed)
+	}
+}
+
+func TestTCPWriter_DialFailure(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	if _, err := influxdb.NewTCPWriter(addr); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -1,23 +1,105 @@
 package influxdb
 
-import "time"
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 var _ Writer = &TimedWriter{}
 
+// FlushErrorPolicy determines what happens to the buffered data when an
+// automatic flush fails.
+type FlushErrorPolicy int
+
+const (
+	// RetainOnError keeps the data that could not be written in the buffer
+	// so it is retried by the next flush. Data that the server rejected as
+	// invalid with a 400 response or a partial write would be rejected
+	// again, so it is discarded instead so it does not block the data
+	// written after it.
+	RetainOnError FlushErrorPolicy = iota
+
+	// DropOnError discards the data that could not be written.
+	DropOnError
+)
+
+// TimedWriterOptions is a set of configuration options for configuring a
+// TimedWriter.
+type TimedWriterOptions struct {
+	// ErrorHandler is called with the error from each failed automatic
+	// flush. It is called from the goroutine performing the flushes so it
+	// should not block for long.
+	ErrorHandler func(err error)
+
+	// ErrorPolicy determines what happens to the buffered data when an
+	// automatic flush fails. The default is to retain it unless the server
+	// rejected it permanently.
+	ErrorPolicy FlushErrorPolicy
+
+	// Logger receives debug and warning events for the flushes. If this is
+	// nil and the BufferedWriter wraps an HTTPWriter, the Logger of that
+	// writer's Client is used.
+	Logger Logger
+}
+
+// TimedWriterStats holds counters for the flushes performed by a
+// TimedWriter.
+type TimedWriterStats struct {
+	// Flushes is the number of automatic flushes that have been attempted.
+	Flushes int64
+
+	// FailedFlushes is the number of automatic flushes that returned an error.
+	FailedFlushes int64
+
+	// DroppedBytes is the number of bytes discarded after failed flushes.
+	DroppedBytes int64
+
+	// FlushTime is the total time spent performing automatic flushes.
+	FlushTime time.Duration
+}
+
 // TimedWriter wraps a BufferedWriter and automatically flushes it after each
-// duration passes.
+// duration passes. It is safe to call Write and Flush concurrently with the
+// automatic flushes.
 type TimedWriter struct {
 	*BufferedWriter
+	opt    TimedWriterOptions
 	ticker *time.Ticker
 	done   chan struct{}
+	once   sync.Once
+
+	// flushing is held by the loop during an automatic flush so Stop can
+	// wait for it without waiting for the ErrorHandler.
+	flushing sync.Mutex
+
+	flushes       int64
+	failedFlushes int64
+	droppedBytes  int64
+	flushTime     int64
 }
 
 // NewTimedWriter creates a new TimedWriter from a BufferedWriter and will
 // flush every d time.Duration. After the TimedWriter is created, it must be
-// stopped using Stop or this will leak a goroutine that will run infinitely.
+// stopped using Stop or Close or this will leak a goroutine that will run
+// infinitely.
 func NewTimedWriter(bw *BufferedWriter, d time.Duration) *TimedWriter {
+	return NewTimedWriterOptions(bw, d, TimedWriterOptions{})
+}
+
+// NewTimedWriterOptions creates a new TimedWriter that handles failed
+// automatic flushes using the options.
+func NewTimedWriterOptions(bw *BufferedWriter, d time.Duration, opt TimedWriterOptions) *TimedWriter {
+	if opt.Logger == nil {
+		if hw, ok := bw.w.(*HTTPWriter); ok {
+			opt.Logger = hw.c.Logger
+		}
+	}
+	opt.Logger = loggerOrNop(opt.Logger)
+
 	w := &TimedWriter{
 		BufferedWriter: bw,
+		opt:            opt,
 		ticker:         time.NewTicker(d),
 		done:           make(chan struct{}),
 	}
@@ -25,11 +107,35 @@ func NewTimedWriter(bw *BufferedWriter, d time.Duration) *TimedWriter {
 	return w
 }
 
-// Stop stops this TimedWriter. This method will panic if called multiple
-// times.
+// Stop stops the automatic flushes of this TimedWriter without flushing any
+// data that is still buffered. It waits for a flush that is in progress to
+// finish, but not for the ErrorHandler, so Stop and Close can be called from
+// the ErrorHandler. It is safe to call Stop multiple times.
 func (w *TimedWriter) Stop() {
-	w.ticker.Stop()
-	close(w.done)
+	w.once.Do(func() {
+		w.ticker.Stop()
+		close(w.done)
+	})
+	w.flushing.Lock()
+	w.flushing.Unlock()
+}
+
+// Close stops this TimedWriter and performs a final flush of any buffered
+// data. The final flush is counted in the stats and follows the ErrorPolicy,
+// but its error is returned instead of being passed to the ErrorHandler.
+func (w *TimedWriter) Close() error {
+	w.Stop()
+	return w.flush()
+}
+
+// Stats returns a snapshot of the flush counters.
+func (w *TimedWriter) Stats() TimedWriterStats {
+	return TimedWriterStats{
+		Flushes:       atomic.LoadInt64(&w.flushes),
+		FailedFlushes: atomic.LoadInt64(&w.failedFlushes),
+		DroppedBytes:  atomic.LoadInt64(&w.droppedBytes),
+		FlushTime:     time.Duration(atomic.LoadInt64(&w.flushTime)),
+	}
 }
 
 // loop will automatically flush the BufferedWriter each interval or until the
@@ -38,10 +144,43 @@ func (w *TimedWriter) loop() {
 	for {
 		select {
 		case <-w.ticker.C:
-			// TODO(jsternberg): Add some way to perform logging.
-			w.Flush()
+			if err := w.tick(); err != nil && w.opt.ErrorHandler != nil {
+				w.opt.ErrorHandler(err)
+			}
 		case <-w.done:
 			return
 		}
 	}
 }
+
+// tick performs an automatic flush unless the TimedWriter has been stopped.
+func (w *TimedWriter) tick() error {
+	w.flushing.Lock()
+	defer w.flushing.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	default:
+		return w.flush()
+	}
+}
+
+// flush flushes the BufferedWriter, updates the counters and applies the
+// ErrorPolicy if the flush fails.
+func (w *TimedWriter) flush() error {
+	atomic.AddInt64(&w.flushes, 1)
+	start := time.Now()
+	dropped, err := w.flushOrDiscard(func(err error) bool {
+		return w.opt.ErrorPolicy == DropOnError || isPermanent(err)
+	})
+	atomic.AddInt64(&w.flushTime, int64(time.Since(start)))
+	if err != nil {
+		atomic.AddInt64(&w.failedFlushes, 1)
+		atomic.AddInt64(&w.droppedBytes, int64(dropped))
+		w.opt.Logger.Warn("flush failed", "duration", time.Since(start), "dropped_bytes", dropped, "buffered_bytes", w.Buffered(), "error", err)
+		return err
+	}
+	w.opt.Logger.Debug("flush", "duration", time.Since(start))
+	return nil
+}
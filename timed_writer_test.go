@@ -2,6 +2,9 @@ package influxdb_test
 
 import (
 	"bytes"
+	"errors"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -29,11 +32,172 @@ func TestTimedWriter(t *testing.T) {
 
 	// Wrap in a timed writer and wait until the interval passes.
 	w := influxdb.NewTimedWriter(bw, 10*time.Millisecond)
-	defer w.Stop()
 	<-time.After(20 * time.Millisecond)
 
+	// Stop the writer so the buffer can be read without racing a flush.
+	w.Stop()
+
 	// Data should have been written.
 	if have, want := buf.Len(), 26; have != want {
 		t.Fatalf("unexpected buffer length: have=%#v want=%#v", have, want)
 	}
 }
+
+func TestTimedWriter_Concurrent(t *testing.T) {
+	var buf bytes.Buffer
+	bw := influxdb.NewBufferedWriterSize(&buf, 64)
+	w := influxdb.NewTimedWriter(bw, time.Millisecond)
+
+	pt := influxdb.Point{
+		Name:   "cpu",
+		Tags:   influxdb.Tags{{Key: "host", Value: "server01"}},
+		Fields: map[string]interface{}{"value": 5.0},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := pt.WriteTo(w); err != nil {
+					t.Errorf("unexpected error: %s", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Close should perform a final flush and can be followed by Stop.
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	w.Stop()
+
+	if have, want := buf.Len(), 26*400; have != want {
+		t.Fatalf("unexpected buffer length: have=%#v want=%#v", have, want)
+	}
+	if have, want := strings.Count(buf.String(), "cpu,host=server01 value=5\n"), 400; have != want {
+		t.Fatalf("unexpected number of lines: have=%#v want=%#v", have, want)
+	}
+}
+
+// failingWriter fails every write until it is told to succeed. It fails
+// with err or, if that is nil, a generic error.
+type failingWriter struct {
+	mu   sync.Mutex
+	fail bool
+	err  error
+	buf  bytes.Buffer
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.fail {
+		if w.err != nil {
+			return 0, w.err
+		}
+		return 0, errors.New("write failed")
+	}
+	return w.buf.Write(p)
+}
+
+func (w *failingWriter) setFail(fail bool) {
+	w.mu.Lock()
+	w.fail = fail
+	w.mu.Unlock()
+}
+
+func TestTimedWriter_ErrorHandler(t *testing.T) {
+	for _, tt := range []struct {
+		policy influxdb.FlushErrorPolicy
+		err    error
+		want   string
+	}{
+		{policy: influxdb.RetainOnError, want: "cpu value=5\n"},
+		{policy: influxdb.DropOnError, want: ""},
+		{policy: influxdb.RetainOnError, err: influxdb.ErrHTTP{StatusCode: 503, Message: "unavailable"}, want: "cpu value=5\n"},
+		{policy: influxdb.RetainOnError, err: influxdb.ErrHTTP{StatusCode: 401, Message: "authorization failed"}, want: "cpu value=5\n"},
+		{policy: influxdb.RetainOnError, err: influxdb.ErrHTTP{StatusCode: 404, Message: "database not found"}, want: "cpu value=5\n"},
+		{policy: influxdb.RetainOnError, err: influxdb.ErrHTTP{StatusCode: 400, Message: "unable to parse"}, want: ""},
+		{policy: influxdb.RetainOnError, err: influxdb.ErrPartialWrite{Err: "partial write"}, want: ""},
+	} {
+		fw := &failingWriter{fail: true, err: tt.err}
+		errs := make(chan error, 1)
+		w := influxdb.NewTimedWriterOptions(influxdb.NewBufferedWriter(fw), time.Millisecond, influxdb.TimedWriterOptions{
+			ErrorHandler: func(err error) {
+				select {
+				case errs <- err:
+				default:
+				}
+			},
+			ErrorPolicy: tt.policy,
+		})
+
+		if _, err := w.Write([]byte("cpu value=5\n")); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		select {
+		case err := <-errs:
+			want := "write failed"
+			if tt.err != nil {
+				want = tt.err.Error()
+			}
+			if have := err.Error(); have != want {
+				t.Fatalf("unexpected error: have=%#v want=%#v", have, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("error handler was not called")
+		}
+		w.Stop()
+
+		stats := w.Stats()
+		if stats.FailedFlushes == 0 {
+			t.Fatalf("expected failed flushes to be counted: %#v", stats)
+		}
+
+		// Close performs a final flush which should only write data that was retained.
+		fw.setFail(false)
+		if err := w.Close(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if have := fw.buf.String(); have != tt.want {
+			t.Fatalf("unexpected output: have=%#v want=%#v", have, tt.want)
+		}
+
+		if tt.want == "" {
+			if have, want := w.Stats().DroppedBytes, int64(12); have != want {
+				t.Fatalf("unexpected dropped bytes: have=%#v want=%#v", have, want)
+			}
+		}
+	}
+}
+
+func TestTimedWriter_CloseFromErrorHandler(t *testing.T) {
+	var (
+		w      *influxdb.TimedWriter
+		once   sync.Once
+		closed = make(chan error, 1)
+	)
+	w = influxdb.NewTimedWriterOptions(influxdb.NewBufferedWriter(&failingWriter{fail: true}), time.Millisecond, influxdb.TimedWriterOptions{
+		ErrorHandler: func(err error) {
+			once.Do(func() { closed <- w.Close() })
+		},
+	})
+	defer w.Stop()
+
+	if _, err := w.Write([]byte("cpu value=5\n")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	select {
+	case err := <-closed:
+		if have, want := err.Error(), "write failed"; have != want {
+			t.Fatalf("unexpected error: have=%#v want=%#v", have, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close called from the error handler did not return")
+	}
+}
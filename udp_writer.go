@@ -11,16 +11,32 @@ var _ Writer = &UDPWriter{}
 // UDPWriter is a simple writer that will write points over udp.
 type UDPWriter struct {
 	conn net.Conn
+	p    Protocol
+
+	// Logger receives debug and warning events for the writes. If this is
+	// left nil, nothing is logged.
+	Logger Logger
 }
 
 // NewUDPWriter creates a new UDPWriter that will be sent to the specified
-// address and will be encoded with the given protocol.
+// address and will be encoded with the DefaultWriteProtocol.
 func NewUDPWriter(addr string) (*UDPWriter, error) {
+	return NewUDPWriterProtocol(addr, DefaultWriteProtocol)
+}
+
+// NewUDPWriterProtocol creates a new UDPWriter that will be sent to the
+// specified address and will be encoded with the given protocol. The
+// precision of the protocol should match the precision the UDP listener on
+// the server has been configured with.
+func NewUDPWriterProtocol(addr string, protocol Protocol) (*UDPWriter, error) {
 	conn, err := net.Dial("udp", addr)
 	if err != nil {
 		return nil, err
 	}
-	return &UDPWriter{conn: conn}, nil
+	if protocol == nil {
+		protocol = DefaultWriteProtocol
+	}
+	return &UDPWriter{conn: conn, p: protocol}, nil
 }
 
 // Write will write the data directly to the UDP socket.
@@ -28,13 +44,23 @@ func (w *UDPWriter) Write(data []byte) (n int, err error) {
 	if len(data) == 0 {
 		return 0, nil
 	}
-	return w.conn.Write(data)
+
+	log := loggerOrNop(w.Logger)
+	if len(data) > MaxUDPPayloadSize {
+		log.Warn("udp payload exceeds the maximum payload size", "addr", w.conn.RemoteAddr().String(), "bytes", len(data), "max_bytes", MaxUDPPayloadSize)
+	}
+	n, err = w.conn.Write(data)
+	if err != nil {
+		log.Warn("udp write failed", "addr", w.conn.RemoteAddr().String(), "bytes", len(data), "error", err)
+		return n, err
+	}
+	log.Debug("udp write", "addr", w.conn.RemoteAddr().String(), "bytes", n)
+	return n, nil
 }
 
 // Protocol returns the protocol associated with this UDP writer.
-// This will always be the DefaultWriteProtocol.
 func (w *UDPWriter) Protocol() Protocol {
-	return DefaultWriteProtocol
+	return w.p
 }
 
 // Close closes the UDP socket.
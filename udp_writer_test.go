@@ -161,3 +161,63 @@ func TestUDPWriter_MultiplePayloads(t *testing.T) {
 		timer.Stop()
 	}
 }
+
+func TestUDPWriter_WithPrecision(t *testing.T) {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	type resp struct {
+		out string
+		err error
+	}
+	in := make(chan resp, 5)
+	go func() {
+		buf := make([]byte, 1024)
+		n, _, err := conn.ReadFromUDP(buf)
+
+		var out []byte
+		if err == nil {
+			out = make([]byte, n)
+			copy(out, buf[:n])
+		}
+		in <- resp{out: string(out), err: err}
+		close(in)
+	}()
+
+	protocol := influxdb.WithPrecision(influxdb.DefaultWriteProtocol, influxdb.PrecisionSecond)
+	w, err := influxdb.NewUDPWriterProtocol(conn.LocalAddr().String(), protocol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if got, want := influxdb.GetPrecision(w.Protocol()), influxdb.PrecisionSecond; got != want {
+		t.Fatalf("unexpected precision: got=%q want=%q", got, want)
+	}
+
+	points := []influxdb.Point{{
+		Name:   "cpu",
+		Fields: map[string]interface{}{"value": 5.0},
+		Time:   time.Unix(10, 500000000),
+	}}
+	if _, err := influxdb.WritePoints(w, points); err != nil {
+		t.Fatalf("unable to write udp message: %s", err)
+	}
+
+	timer := time.NewTimer(100 * time.Millisecond)
+	select {
+	case <-timer.C:
+		t.Fatal("no udp message received")
+	case r := <-in:
+		timer.Stop()
+
+		if r.err != nil {
+			t.Fatalf("error reading from udp socket: %s", r.err)
+		} else if got, want := r.out, "cpu value=5 10\n"; got != want {
+			t.Fatalf("unexpected udp message: got=%q want=%q", got, want)
+		}
+	}
+}
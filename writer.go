@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"io"
 	"net/url"
+	"sort"
 	"strings"
+	"time"
 )
 
 // WriteOptions is a set of configuration options for configuring writers.
@@ -34,11 +36,21 @@ type Writer interface {
 //
 // After writing all of the points, the Flush method is called on the io.Writer
 // if it supports that method.
+//
+// When writing to an HTTPWriter, the points are sent in a single request. If
+// the server drops some of them, the returned ErrPartialWrite contains the
+// indexes of the dropped points that the server identified.
 func WritePoints(w io.Writer, points []Point) (n int, err error) {
 	if len(points) == 0 {
 		return 0, nil
 	}
 
+	// Write all of the points in a single request to an HTTPWriter so any
+	// dropped lines from a partial write can be mapped back to the points.
+	if w, ok := w.(*HTTPWriter); ok {
+		return w.writePoints(points)
+	}
+
 	for _, pt := range points {
 		c, err := pt.WriteTo(w)
 		if err != nil {
@@ -102,13 +114,29 @@ func (w *HTTPWriter) Write(data []byte) (n int, err error) {
 		req.SetBasicAuth(w.c.Auth.Username, w.c.Auth.Password)
 	}
 
-	resp, err := w.c.Do(req)
+	log := w.c.logger()
+	start := time.Now()
+	attrs := func(keysAndValues ...interface{}) []interface{} {
+		return append([]interface{}{
+			"db", w.Database,
+			"rp", w.RetentionPolicy,
+			"bytes", len(data),
+			"duration", time.Since(start),
+		}, keysAndValues...)
+	}
+
+	resp, err := w.c.do(OperationWrite, req)
 	if err != nil {
+		log.Warn("write failed", attrs("error", err)...)
 		return 0, err
 	}
+	reqID := requestID(resp)
 
 	switch resp.StatusCode / 100 {
 	case 2:
+		log.Debug("write", attrs("request_id", reqID)...)
+		w.c.statistics().recordWrite(data, false)
+		resp.Body.Close()
 		return len(data), nil
 	case 4:
 		// This is a client error. Read the error message to learn what type of
@@ -116,15 +144,56 @@ func (w *HTTPWriter) Write(data []byte) (n int, err error) {
 		err := ReadError(resp)
 		if strings.HasPrefix(err.Error(), "partial write:") {
 			// So we DID write, but it was a partial write. Wrap the error message.
-			return len(data), ErrPartialWrite{Err: err.Error()}
+			log.Warn("partial write", attrs("request_id", reqID, "error", err)...)
+			w.c.statistics().recordWrite(data, true)
+			return len(data), parsePartialWrite(err.Error(), data)
 		}
+		log.Warn("write failed", attrs("request_id", reqID, "error", err)...)
 		return 0, err
 	default:
 		// The server should never actually return anything other than the
 		// above, but catch any weird status codes that might get thrown by a
 		// proxy or something.
-		return 0, ReadError(resp)
+		err := ReadError(resp)
+		log.Warn("write failed", attrs("request_id", reqID, "error", err)...)
+		return 0, err
+	}
+}
+
+// writePoints encodes the points and writes them in a single request. If
+// the write is partial, the dropped lines are mapped back to the points.
+func (w *HTTPWriter) writePoints(points []Point) (int, error) {
+	p := w.Protocol()
+	if p == nil {
+		p = DefaultWriteProtocol
+	}
+
+	// Keep track of the line each point starts on. A Protocol may encode a
+	// point using more than one line.
+	var buf bytes.Buffer
+	starts := make([]int, len(points))
+	lines := 0
+	for i := range points {
+		starts[i] = lines + 1
+		start := buf.Len()
+		if _, err := p.Encode(&buf, &points[i]); err != nil {
+			return 0, err
+		}
+		lines += bytes.Count(buf.Bytes()[start:], []byte("\n"))
+	}
+
+	n, err := w.Write(buf.Bytes())
+	if e, ok := err.(ErrPartialWrite); ok {
+		for _, line := range e.Lines {
+			// Find the last point that starts on or before the line.
+			i := sort.SearchInts(starts, line+1) - 1
+			if i >= 0 && (len(e.Points) == 0 || e.Points[len(e.Points)-1] != i) {
+				e.Points = append(e.Points, i)
+			}
+		}
+		err = e
 	}
+	return n, err
 }
 
 // Protocol is the Protocol that will be used to write to the HTTP endpoint.
@@ -1,9 +1,12 @@
 package influxdb_test
 
 import (
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -110,3 +113,120 @@ func TestHttpWriter_ConsistencyAndPrecision(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestWritePoints_PartialWrite(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := strings.Count(string(body), "\n"), 4; got != want {
+			t.Errorf("got %d lines; want %d", got, want)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"error":"partial write: field type conflict: input field \"value\" on measurement \"cpu\" is type integer, already exists as type float dropped=2"}`)
+	}))
+	defer server.Close()
+
+	client, err := influxdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer := client.Writer()
+	writer.Database = "db0"
+	points := []influxdb.Point{
+		{Name: "cpu", Fields: map[string]interface{}{"value": 1.5}},
+		{Name: "cpu", Fields: map[string]interface{}{"value": int64(2)}},
+		{Name: "mem", Fields: map[string]interface{}{"value": int64(3)}},
+		{Name: "cpu", Fields: map[string]interface{}{"value": int64(4)}},
+	}
+	_, err = influxdb.WritePoints(writer, points)
+
+	e, ok := err.(influxdb.ErrPartialWrite)
+	if !ok {
+		t.Fatalf("got error %#v; want %T", err, e)
+	}
+	if got, want := e.Dropped, 2; got != want {
+		t.Errorf("Dropped = %d; want %d", got, want)
+	}
+	if got, want := e.Reasons, map[string]int{"field type conflict": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Reasons = %v; want %v", got, want)
+	}
+	if got, want := e.Lines, []int{2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines = %v; want %v", got, want)
+	}
+	if got, want := e.Points, []int{1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Points = %v; want %v", got, want)
+	}
+}
+
+func TestWritePoints_PartialWrite_Shards(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"error":"partial write: field type conflict: input field \"value\" on measurement \"cpu\" is type integer, already exists as type float dropped=1"}`)
+	}))
+	defer server.Close()
+
+	client, err := influxdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The points are in different shards and only the shard that already
+	// has a float field rejects its point. Both points match the conflict.
+	points := []influxdb.Point{
+		{Name: "cpu", Fields: map[string]interface{}{"value": int64(1)}, Time: time.Unix(0, 0)},
+		{Name: "cpu", Fields: map[string]interface{}{"value": int64(2)}, Time: time.Unix(86400*30, 0)},
+		{Name: "mem", Fields: map[string]interface{}{"value": int64(3)}, Time: time.Unix(0, 0)},
+	}
+	_, err = influxdb.WritePoints(client.Writer(), points)
+
+	e, ok := err.(influxdb.ErrPartialWrite)
+	if !ok {
+		t.Fatalf("got error %#v; want %T", err, e)
+	}
+	if got, want := e.Dropped, 1; got != want {
+		t.Errorf("Dropped = %d; want %d", got, want)
+	}
+	if got, want := e.Lines, []int{1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines = %v; want %v", got, want)
+	}
+	if got, want := e.Points, []int{0, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Points = %v; want %v", got, want)
+	}
+}
+
+func TestHTTPWriter_PartialWrite_Unparsable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"error":"partial write: unable to parse 'cpu value=': missing field value\nunable to parse 'mem,host value=1': missing tag value dropped=2"}`)
+	}))
+	defer server.Close()
+
+	client, err := influxdb.NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer := client.Writer()
+	_, err = writer.Write([]byte("cpu value=1\ncpu value=\ndisk value=2\nmem,host value=1\n"))
+
+	e, ok := err.(influxdb.ErrPartialWrite)
+	if !ok {
+		t.Fatalf("got error %#v; want %T", err, e)
+	}
+	if got, want := e.Dropped, 2; got != want {
+		t.Errorf("Dropped = %d; want %d", got, want)
+	}
+	if got, want := e.Reasons, map[string]int{"unable to parse": 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Reasons = %v; want %v", got, want)
+	}
+	if got, want := e.Lines, []int{2, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines = %v; want %v", got, want)
+	}
+	if e.Points != nil {
+		t.Errorf("Points = %v; want nil", e.Points)
+	}
+}